@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"strconv"
+	"strings"
 
 	"github.com/esquivias/interpreter/token"
 )
@@ -58,9 +60,13 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+// String returns the statements one per line, so a; b does not read back as the single identifier ab
 func (p *Program) String() string {
 	var out bytes.Buffer
-	for _, s := range p.Statements {
+	for i, s := range p.Statements {
+		if i > 0 {
+			out.WriteString("\n")
+		}
 		out.WriteString(s.String())
 	}
 	return out.String()
@@ -89,16 +95,62 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+/*
+ * Char Literal
+ */
+
+// CharLiteral struct
+type CharLiteral struct {
+	Token token.Token
+	Value rune // the character's Unicode code point
+}
+
+// expressionNode function on CharLiteral
+func (cl *CharLiteral) expressionNode() {}
+
+// TokenLiteral function on CharLiteral
+func (cl *CharLiteral) TokenLiteral() string {
+	return cl.Token.Literal
+}
+
+// String function on CharLiteral renders the character quoted and escaped, e.g. '\n'
+func (cl *CharLiteral) String() string {
+	return strconv.QuoteRune(cl.Value)
+}
+
+/*
+ * Null Literal
+ */
+
+// NullLiteral struct
+type NullLiteral struct {
+	Token token.Token // the 'null' token
+}
+
+// expressionNode function on NullLiteral
+func (nl *NullLiteral) expressionNode() {}
+
+// TokenLiteral function on NullLiteral
+func (nl *NullLiteral) TokenLiteral() string {
+	return nl.Token.Literal
+}
+
+// String function on NullLiteral
+func (nl *NullLiteral) String() string {
+	return nl.Token.Literal
+}
+
 /*
  * LetStatement
  */
 
 // LetStatement struct
 type LetStatement struct {
-	// let x = 5
+	// let x = 5 or let x: int = 5
 	Token token.Token // token (token.LET)
 	Name  *Identifier // identifier of the binding (token.IDENT, x)
-	Value Expression  // expression that produces the value (INT 5)
+	Type  *Identifier // optional declared type name (token.IDENT, int); nil if not annotated
+	Value Expression  // expression that produces the value (INT 5); nil for let x;
 }
 
 // statementNode function on LetStatement
@@ -114,9 +166,11 @@ func (ls *LetStatement) String() string {
 
 	out.WriteString(ls.TokenLiteral() + " ")
 	out.WriteString(ls.Name.String())
-	out.WriteString(" = ")
-
+	if ls.Type != nil {
+		out.WriteString(": " + ls.Type.String())
+	}
 	if ls.Value != nil {
+		out.WriteString(" = ")
 		out.WriteString(ls.Value.String())
 	}
 
@@ -125,6 +179,40 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+/*
+ * Assign Expression
+ */
+
+// AssignExpression struct; its value is the assigned value, so chains like a = b = 5 evaluate right to left:
+// 5 is assigned to b first, then the result to a. In a let value (let a = b = 5;) every chained name is bound.
+type AssignExpression struct {
+	// <name> = <value>
+	Token token.Token // the '=' token
+	Name  *Identifier
+	Value Expression
+}
+
+// expressionNode function on AssignExpression
+func (ae *AssignExpression) expressionNode() {}
+
+// TokenLiteral function on AssignExpression
+func (ae *AssignExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+
+// String function on AssignExpression
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Name.String())
+	out.WriteString(" = ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
 /*
  * Expression Statement
  */
@@ -173,9 +261,10 @@ func (rs *ReturnStatement) TokenLiteral() string {
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(rs.TokenLiteral() + " ")
+	out.WriteString(rs.TokenLiteral())
 
 	if rs.ReturnValue != nil {
+		out.WriteString(" ")
 		out.WriteString(rs.ReturnValue.String())
 	}
 
@@ -184,10 +273,51 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
+/*
+ * Break and Continue Statements
+ */
+
+// BreakStatement struct; stops the innermost loop
+type BreakStatement struct {
+	Token token.Token // the 'break' token
+}
+
+// statementNode function on BreakStatement
+func (bs *BreakStatement) statementNode() {}
+
+// TokenLiteral function on BreakStatement
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+// String function on BreakStatement
+func (bs *BreakStatement) String() string {
+	return bs.TokenLiteral() + ";"
+}
+
+// ContinueStatement struct; skips to the next iteration of the innermost loop
+type ContinueStatement struct {
+	Token token.Token // the 'continue' token
+}
+
+// statementNode function on ContinueStatement
+func (cs *ContinueStatement) statementNode() {}
+
+// TokenLiteral function on ContinueStatement
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+// String function on ContinueStatement
+func (cs *ContinueStatement) String() string {
+	return cs.TokenLiteral() + ";"
+}
+
 // PrefixExpression struct
 type PrefixExpression struct {
 	Token    token.Token
-	Operator string
+	Operator string     // operator literal, used for display
+	OpType   token.Type // operator token type, e.g. token.BANG
 	Right    Expression
 }
 
@@ -207,7 +337,40 @@ func (pe *PrefixExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(pe.Operator)
-	out.WriteString(pe.Right.String())
+	right := pe.Right.String()
+	// keep - -5 from reading as --5
+	if strings.HasPrefix(right, "-") && strings.HasSuffix(pe.Operator, "-") {
+		out.WriteString(" ")
+	}
+	out.WriteString(right)
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// PostfixExpression struct
+type PostfixExpression struct {
+	Token    token.Token // The operator token, e.g. ++
+	Left     Expression
+	Operator string     // operator literal, used for display
+	OpType   token.Type // operator token type, e.g. token.INC
+}
+
+// expressionNode function on PostfixExpression
+func (pe *PostfixExpression) expressionNode() {}
+
+// TokenLiteral function on PostfixExpression
+func (pe *PostfixExpression) TokenLiteral() string {
+	return pe.Token.Literal
+}
+
+// String function on PostfixExpression
+func (pe *PostfixExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(pe.Left.String())
+	out.WriteString(pe.Operator)
 	out.WriteString(")")
 
 	return out.String()
@@ -217,7 +380,8 @@ func (pe *PrefixExpression) String() string {
 type InfixExpression struct {
 	Token    token.Token // The operator token, e.g. +
 	Left     Expression
-	Operator string
+	Operator string     // operator literal, used for display
+	OpType   token.Type // operator token type, e.g. token.PLUS
 	Right    Expression
 }
 
@@ -241,3 +405,309 @@ func (ie *InfixExpression) String() string {
 	out.WriteString(")")
 	return out.String()
 }
+
+/*
+ * Block Statement
+ */
+
+// BlockStatement struct contains the statements between a pair of braces
+type BlockStatement struct {
+	Token      token.Token // the '{' token
+	Statements []Statement
+}
+
+// statementNode function on BlockStatement
+func (bs *BlockStatement) statementNode() {}
+
+// TokenLiteral function on BlockStatement
+func (bs *BlockStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+// String function on BlockStatement
+func (bs *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("{ ")
+	for _, s := range bs.Statements {
+		out.WriteString(s.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+/*
+ * While Statement
+ */
+
+// WhileStatement struct
+type WhileStatement struct {
+	// while (<condition>) { <body> }
+	Token     token.Token // the 'while' token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+// statementNode function on WhileStatement
+func (ws *WhileStatement) statementNode() {}
+
+// TokenLiteral function on WhileStatement
+func (ws *WhileStatement) TokenLiteral() string {
+	return ws.Token.Literal
+}
+
+// String function on WhileStatement
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while (")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(") ")
+	out.WriteString(ws.Body.String())
+
+	return out.String()
+}
+
+/*
+ * Do While Statement
+ */
+
+// DoWhileStatement struct; the body runs once before the condition is first checked
+type DoWhileStatement struct {
+	// do { <body> } while (<condition>)
+	Token     token.Token // the 'do' token
+	Body      *BlockStatement
+	Condition Expression
+}
+
+// statementNode function on DoWhileStatement
+func (dws *DoWhileStatement) statementNode() {}
+
+// TokenLiteral function on DoWhileStatement
+func (dws *DoWhileStatement) TokenLiteral() string {
+	return dws.Token.Literal
+}
+
+// String function on DoWhileStatement
+func (dws *DoWhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("do ")
+	out.WriteString(dws.Body.String())
+	out.WriteString(" while (")
+	out.WriteString(dws.Condition.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+/*
+ * For Statement
+ */
+
+// ForStatement struct; Init, Condition, and Post are nil when their section is empty
+type ForStatement struct {
+	// for (<init>; <condition>; <post>) { <body> }
+	Token     token.Token // the 'for' token
+	Init      Statement
+	Condition Expression
+	Post      Expression
+	Body      *BlockStatement
+}
+
+// statementNode function on ForStatement
+func (fs *ForStatement) statementNode() {}
+
+// TokenLiteral function on ForStatement
+func (fs *ForStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+// String function on ForStatement
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fs.Init != nil {
+		// let and return statements render their own terminating semicolon
+		out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+	}
+	out.WriteString("; ")
+	if fs.Condition != nil {
+		out.WriteString(fs.Condition.String())
+	}
+	out.WriteString("; ")
+	if fs.Post != nil {
+		out.WriteString(fs.Post.String())
+	}
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+/*
+ * If Expression
+ */
+
+// IfExpression struct
+type IfExpression struct {
+	// <consequence> if <condition> else <alternative>
+	Token       token.Token // the 'if' token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+// expressionNode function on IfExpression
+func (ie *IfExpression) expressionNode() {}
+
+// TokenLiteral function on IfExpression
+func (ie *IfExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+// String function on IfExpression
+func (ie *IfExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ie.Consequence.String())
+	out.WriteString(" if ")
+	out.WriteString(ie.Condition.String())
+	out.WriteString(" else ")
+	out.WriteString(ie.Alternative.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+/*
+ * Switch Expression
+ */
+
+// SwitchExpression struct; the first case whose value equals the subject runs, otherwise the default, if any
+type SwitchExpression struct {
+	// switch (<subject>) { case <value>: <statements> ... default: <statements> }
+	Token   token.Token // the 'switch' token
+	Subject Expression
+	Cases   []*CaseClause
+	Default *BlockStatement // nil if there is no default
+}
+
+// expressionNode function on SwitchExpression
+func (se *SwitchExpression) expressionNode() {}
+
+// TokenLiteral function on SwitchExpression
+func (se *SwitchExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+
+// String function on SwitchExpression
+func (se *SwitchExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("switch (")
+	out.WriteString(se.Subject.String())
+	out.WriteString(") { ")
+	for _, c := range se.Cases {
+		out.WriteString(c.String())
+		out.WriteString(" ")
+	}
+	if se.Default != nil {
+		out.WriteString("default: ")
+		out.WriteString(se.Default.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// CaseClause struct; the statements up to the next case, default, or closing brace form its body
+type CaseClause struct {
+	// case <value>: <statements>
+	Token token.Token // the 'case' token
+	Value Expression
+	Body  *BlockStatement
+}
+
+// TokenLiteral function on CaseClause
+func (cc *CaseClause) TokenLiteral() string {
+	return cc.Token.Literal
+}
+
+// String function on CaseClause
+func (cc *CaseClause) String() string {
+	return "case " + cc.Value.String() + ": " + cc.Body.String()
+}
+
+/*
+ * Try Statement
+ */
+
+// TryStatement struct
+type TryStatement struct {
+	// try { <body> } catch (<parameter>) { <catch> }
+	Token     token.Token // the 'try' token
+	Body      *BlockStatement
+	Parameter *Identifier // bound to the error raised by the body
+	Catch     *BlockStatement
+}
+
+// statementNode function on TryStatement
+func (ts *TryStatement) statementNode() {}
+
+// TokenLiteral function on TryStatement
+func (ts *TryStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+// String function on TryStatement
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(ts.Body.String())
+	out.WriteString(" catch (")
+	out.WriteString(ts.Parameter.String())
+	out.WriteString(") ")
+	out.WriteString(ts.Catch.String())
+
+	return out.String()
+}
+
+/*
+ * Member Expression
+ */
+
+// MemberExpression struct
+type MemberExpression struct {
+	// <object>.<property>
+	Token    token.Token // the '.' token
+	Object   Expression
+	Property *Identifier
+}
+
+// expressionNode function on MemberExpression
+func (me *MemberExpression) expressionNode() {}
+
+// TokenLiteral function on MemberExpression
+func (me *MemberExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+// String function on MemberExpression
+func (me *MemberExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(me.Object.String())
+	out.WriteString(".")
+	out.WriteString(me.Property.String())
+	out.WriteString(")")
+
+	return out.String()
+}
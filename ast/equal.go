@@ -0,0 +1,130 @@
+package ast
+
+import (
+	"reflect"
+
+	"github.com/esquivias/interpreter/token"
+)
+
+// Equal returns true if a and b are the same tree: the same node types, operators, literals, and children.
+// Token positions are ignored, so trees parsed from differently laid out source compare equal.
+func Equal(a, b Node) bool {
+	if isNil(a) || isNil(b) {
+		return isNil(a) && isNil(b)
+	}
+
+	switch a := a.(type) {
+	case *Program:
+		b, ok := b.(*Program)
+		return ok && statementsEqual(a.Statements, b.Statements)
+	case *Identifier:
+		b, ok := b.(*Identifier)
+		return ok && tokenEqual(a.Token, b.Token) && a.Value == b.Value
+	case *IntegerLiteral:
+		b, ok := b.(*IntegerLiteral)
+		return ok && tokenEqual(a.Token, b.Token) && a.Value == b.Value
+	case *CharLiteral:
+		b, ok := b.(*CharLiteral)
+		return ok && tokenEqual(a.Token, b.Token) && a.Value == b.Value
+	case *NullLiteral:
+		b, ok := b.(*NullLiteral)
+		return ok && tokenEqual(a.Token, b.Token)
+	case *LetStatement:
+		b, ok := b.(*LetStatement)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Name, b.Name) && Equal(a.Type, b.Type) &&
+			Equal(a.Value, b.Value)
+	case *AssignExpression:
+		b, ok := b.(*AssignExpression)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Name, b.Name) && Equal(a.Value, b.Value)
+	case *ExpressionStatement:
+		b, ok := b.(*ExpressionStatement)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Expression, b.Expression)
+	case *ReturnStatement:
+		b, ok := b.(*ReturnStatement)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.ReturnValue, b.ReturnValue)
+	case *BreakStatement:
+		b, ok := b.(*BreakStatement)
+		return ok && tokenEqual(a.Token, b.Token)
+	case *ContinueStatement:
+		b, ok := b.(*ContinueStatement)
+		return ok && tokenEqual(a.Token, b.Token)
+	case *PrefixExpression:
+		b, ok := b.(*PrefixExpression)
+		return ok && tokenEqual(a.Token, b.Token) && a.Operator == b.Operator && a.OpType == b.OpType &&
+			Equal(a.Right, b.Right)
+	case *PostfixExpression:
+		b, ok := b.(*PostfixExpression)
+		return ok && tokenEqual(a.Token, b.Token) && a.Operator == b.Operator && a.OpType == b.OpType &&
+			Equal(a.Left, b.Left)
+	case *InfixExpression:
+		b, ok := b.(*InfixExpression)
+		return ok && tokenEqual(a.Token, b.Token) && a.Operator == b.Operator && a.OpType == b.OpType &&
+			Equal(a.Left, b.Left) && Equal(a.Right, b.Right)
+	case *BlockStatement:
+		b, ok := b.(*BlockStatement)
+		return ok && tokenEqual(a.Token, b.Token) && statementsEqual(a.Statements, b.Statements)
+	case *WhileStatement:
+		b, ok := b.(*WhileStatement)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Condition, b.Condition) && Equal(a.Body, b.Body)
+	case *DoWhileStatement:
+		b, ok := b.(*DoWhileStatement)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Body, b.Body) && Equal(a.Condition, b.Condition)
+	case *ForStatement:
+		b, ok := b.(*ForStatement)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Init, b.Init) && Equal(a.Condition, b.Condition) &&
+			Equal(a.Post, b.Post) && Equal(a.Body, b.Body)
+	case *IfExpression:
+		b, ok := b.(*IfExpression)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Condition, b.Condition) &&
+			Equal(a.Consequence, b.Consequence) && Equal(a.Alternative, b.Alternative)
+	case *SwitchExpression:
+		b, ok := b.(*SwitchExpression)
+		if !ok || !tokenEqual(a.Token, b.Token) || !Equal(a.Subject, b.Subject) || len(a.Cases) != len(b.Cases) {
+			return false
+		}
+		for i := range a.Cases {
+			if !Equal(a.Cases[i], b.Cases[i]) {
+				return false
+			}
+		}
+		return Equal(a.Default, b.Default)
+	case *CaseClause:
+		b, ok := b.(*CaseClause)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Value, b.Value) && Equal(a.Body, b.Body)
+	case *TryStatement:
+		b, ok := b.(*TryStatement)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Body, b.Body) && Equal(a.Parameter, b.Parameter) &&
+			Equal(a.Catch, b.Catch)
+	case *MemberExpression:
+		b, ok := b.(*MemberExpression)
+		return ok && tokenEqual(a.Token, b.Token) && Equal(a.Object, b.Object) && Equal(a.Property, b.Property)
+	}
+	return false
+}
+
+// isNil returns true for a nil Node, including a nil pointer to a node type stored in a Node
+func isNil(n Node) bool {
+	if n == nil {
+		return true
+	}
+	v := reflect.ValueOf(n)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// tokenEqual compares the type and literal of two tokens, ignoring their positions
+func tokenEqual(a, b token.Token) bool {
+	return a.Type == b.Type && a.Literal == b.Literal
+}
+
+// statementsEqual compares two statement lists element by element
+func statementsEqual(a, b []Statement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
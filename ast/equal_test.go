@@ -0,0 +1,73 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/esquivias/interpreter/token"
+)
+
+func TestEqual(t *testing.T) {
+	// -a * 5, with tokens at the given line
+	tree := func(line int, operator string, opType token.Type) Node {
+		return &Program{
+			Statements: []Statement{
+				&ExpressionStatement{
+					Token: token.Token{Type: token.MINUS, Literal: "-", Line: line},
+					Expression: &InfixExpression{
+						Token: token.Token{Type: opType, Literal: operator, Line: line},
+						Left: &PrefixExpression{
+							Token:    token.Token{Type: token.MINUS, Literal: "-", Line: line},
+							Operator: "-",
+							OpType:   token.MINUS,
+							Right: &Identifier{
+								Token: token.Token{Type: token.IDENT, Literal: "a", Line: line},
+								Value: "a",
+							},
+						},
+						Operator: operator,
+						OpType:   opType,
+						Right: &IntegerLiteral{
+							Token: token.Token{Type: token.INT, Literal: "5", Line: line},
+							Value: 5,
+						},
+					},
+				},
+			},
+		}
+	}
+	let := func(typeName *Identifier) Node {
+		return &LetStatement{
+			Token: token.Token{Type: token.LET, Literal: "let"},
+			Name:  &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x"}, Value: "x"},
+			Type:  typeName,
+		}
+	}
+	intType := &Identifier{Token: token.Token{Type: token.IDENT, Literal: "int"}, Value: "int"}
+
+	tests := []struct {
+		a, b     Node
+		expected bool
+	}{
+		{tree(1, "*", token.ASTERISK), tree(1, "*", token.ASTERISK), true},
+		{tree(1, "*", token.ASTERISK), tree(3, "*", token.ASTERISK), true},
+		{tree(1, "*", token.ASTERISK), tree(1, "/", token.SLASH), false},
+		{tree(1, "*", token.ASTERISK), &Program{}, false},
+		{&Program{}, &Program{Statements: []Statement{}}, true},
+		{let(nil), let(nil), true},
+		{let(intType), let(intType), true},
+		{let(nil), let(intType), false},
+		{let(nil), nil, false},
+		{nil, nil, true},
+		{(*BlockStatement)(nil), nil, true},
+		{&Identifier{Value: "x"}, &IntegerLiteral{}, false},
+	}
+
+	for i, tt := range tests {
+		if actual := Equal(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("tests[%d] - Equal wrong. expected=%t, got=%t", i, tt.expected, actual)
+		}
+		if actual := Equal(tt.b, tt.a); actual != tt.expected {
+			t.Errorf("tests[%d] - Equal not symmetric. expected=%t, got=%t", i, tt.expected, actual)
+		}
+	}
+}
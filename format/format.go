@@ -0,0 +1,216 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/esquivias/interpreter/ast"
+	"github.com/esquivias/interpreter/lexer"
+	"github.com/esquivias/interpreter/parser"
+	"github.com/esquivias/interpreter/token"
+)
+
+// indent is written once per nesting level at the start of a line
+const indent = "\t"
+
+// charEscapes maps the characters a character literal must escape to their escape sequence
+var charEscapes = map[rune]string{
+	0:    `\0`,
+	'\n': `\n`,
+	'\r': `\r`,
+	'\t': `\t`,
+	'\'': `\'`,
+	'\\': `\\`,
+}
+
+// Source parses input and returns it in canonical form: one statement per line, each terminated by a semicolon,
+// single spaces around binary operators, and blocks indented with tabs. Formatting its own output returns it unchanged.
+// Formatting would drop comments, so input containing a comment is an error. If input does not parse, Source returns
+// the parser errors instead.
+func Source(input string) (string, error) {
+	l := lexer.NewWithComments(input)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		if tok.Type == token.COMMENT {
+			return "", fmt.Errorf("%d:%d: comments are not preserved by Source", tok.Line, tok.Column)
+		}
+	}
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if errors := p.ParseErrors(); len(errors) > 0 {
+		messages := make([]string, len(errors))
+		for i, e := range errors {
+			messages[i] = e.Error()
+		}
+		return "", fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+
+	f := &formatter{}
+	for _, s := range program.Statements {
+		f.statement(s)
+	}
+	return f.out.String(), nil
+}
+
+// formatter writes statements to out at the current nesting depth
+type formatter struct {
+	out   bytes.Buffer
+	depth int
+}
+
+// line writes s on its own line at the current depth
+func (f *formatter) line(s string) {
+	f.out.WriteString(strings.Repeat(indent, f.depth))
+	f.write(s)
+	f.out.WriteString("\n")
+}
+
+// write writes s, indenting the lines of a multi-line expression such as a switch to the current depth
+func (f *formatter) write(s string) {
+	f.out.WriteString(strings.Replace(s, "\n", "\n"+strings.Repeat(indent, f.depth), -1))
+}
+
+// statement writes a statement on its own lines
+func (f *formatter) statement(s ast.Statement) {
+	switch s := s.(type) {
+	case *ast.WhileStatement:
+		f.block("while ("+expression(s.Condition)+") ", s.Body)
+	case *ast.DoWhileStatement:
+		f.block("do ", s.Body)
+		// the while continues the line closing the block
+		f.out.Truncate(f.out.Len() - 1)
+		f.write(" while (" + expression(s.Condition) + ");\n")
+	case *ast.ForStatement:
+		header := simpleStatement(s.Init) + ";"
+		if s.Condition != nil {
+			header += " " + expression(s.Condition)
+		}
+		header += ";"
+		if s.Post != nil {
+			header += " " + expression(s.Post)
+		}
+		f.block("for ("+header+") ", s.Body)
+	case *ast.TryStatement:
+		f.block("try ", s.Body)
+		// the catch continues the line closing the try block
+		f.out.Truncate(f.out.Len() - 1)
+		f.out.WriteString(" catch (" + s.Parameter.Value + ") ")
+		f.blockBody(s.Catch)
+	case *ast.BlockStatement:
+		f.block("", s)
+	default:
+		f.line(simpleStatement(s) + ";")
+	}
+}
+
+// block starts a line at the current depth with prefix and writes b
+func (f *formatter) block(prefix string, b *ast.BlockStatement) {
+	f.out.WriteString(strings.Repeat(indent, f.depth))
+	f.write(prefix)
+	f.blockBody(b)
+}
+
+// blockBody writes a block from its opening brace, which continues the current line, to its closing brace line
+func (f *formatter) blockBody(b *ast.BlockStatement) {
+	if len(b.Statements) == 0 {
+		f.out.WriteString("{}\n")
+		return
+	}
+	f.out.WriteString("{\n")
+	f.depth++
+	for _, s := range b.Statements {
+		f.statement(s)
+	}
+	f.depth--
+	f.line("}")
+}
+
+// caseBody writes the statements of a case or default one level deeper than the case
+func (f *formatter) caseBody(b *ast.BlockStatement) {
+	f.depth++
+	for _, s := range b.Statements {
+		f.statement(s)
+	}
+	f.depth--
+}
+
+// simpleStatement returns a let, return, or expression statement on a single line without its terminating semicolon
+func simpleStatement(s ast.Statement) string {
+	switch s := s.(type) {
+	case nil:
+		return ""
+	case *ast.LetStatement:
+		out := "let " + s.Name.Value
+		if s.Type != nil {
+			out += ": " + s.Type.Value
+		}
+		if s.Value == nil {
+			return out
+		}
+		return out + " = " + expression(s.Value)
+	case *ast.ReturnStatement:
+		if s.ReturnValue == nil {
+			return "return"
+		}
+		return "return " + expression(s.ReturnValue)
+	case *ast.ExpressionStatement:
+		return expression(s.Expression)
+	}
+	return strings.TrimSuffix(s.String(), ";")
+}
+
+// expression returns e in source order; the parser only builds trees that read back the same way without grouping
+func expression(e ast.Expression) string {
+	switch e := e.(type) {
+	case nil:
+		return ""
+	case *ast.Identifier:
+		return e.Value
+	case *ast.IntegerLiteral:
+		return e.Token.Literal
+	case *ast.CharLiteral:
+		if escaped, ok := charEscapes[e.Value]; ok {
+			return "'" + escaped + "'"
+		}
+		return "'" + string(e.Value) + "'"
+	case *ast.NullLiteral:
+		return "null"
+	case *ast.PrefixExpression:
+		right := expression(e.Right)
+		// keep - -x from reading back as --x
+		if e.Operator == "-" && strings.HasPrefix(right, "-") {
+			return e.Operator + " " + right
+		}
+		return e.Operator + right
+	case *ast.PostfixExpression:
+		return expression(e.Left) + e.Operator
+	case *ast.InfixExpression:
+		return expression(e.Left) + " " + e.Operator + " " + expression(e.Right)
+	case *ast.AssignExpression:
+		return e.Name.Value + " = " + expression(e.Value)
+	case *ast.IfExpression:
+		return expression(e.Consequence) + " if " + expression(e.Condition) + " else " + expression(e.Alternative)
+	case *ast.MemberExpression:
+		return expression(e.Object) + "." + e.Property.Value
+	case *ast.SwitchExpression:
+		header := "switch (" + expression(e.Subject) + ") "
+		if len(e.Cases) == 0 && e.Default == nil {
+			return header + "{}"
+		}
+		// cases line up with the switch, like gofmt
+		f := &formatter{}
+		f.out.WriteString(header + "{\n")
+		for _, c := range e.Cases {
+			f.line("case " + expression(c.Value) + ":")
+			f.caseBody(c.Body)
+		}
+		if e.Default != nil {
+			f.line("default:")
+			f.caseBody(e.Default)
+		}
+		f.out.WriteString("}")
+		return f.out.String()
+	}
+	return e.String()
+}
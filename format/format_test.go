@@ -0,0 +1,106 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/esquivias/interpreter/parser"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x=5", "let x = 5;\n"},
+		{"let x ; let y:int;", "let x;\nlet y: int;\n"},
+		{"let  x :int=5;x*2", "let x: int = 5;\nx * 2;\n"},
+		{"a+b*c-d/e**f", "a + b * c - d / e ** f;\n"},
+		{"-a;!b;- -c;-5;- -5;!-d;n!;i++;i--", "-a;\n!b;\n- -c;\n-5;\n- -5;\n!-d;\nn!;\ni++;\ni--;\n"},
+		{"x\n-y", "x;\n-y;\n"},
+		{"a ?? b if c else d", "a ?? b if c else d;\n"},
+		{"a = b = o.p", "a = b = o.p;\n"},
+		{"return 'a'; return '\\n'; return '\\''", "return 'a';\nreturn '\\n';\nreturn '\\'';\n"},
+		{"return null", "return null;\n"},
+		{"while (x) { return }\nreturn", "while (x) {\n\treturn;\n}\nreturn;\n"},
+		{"while (x < 10) { x++; while (y) {} }",
+			"while (x < 10) {\n\tx++;\n\twhile (y) {}\n}\n"},
+		{"for (let i = 0; i < 10; i++) { i }", "for (let i = 0; i < 10; i++) {\n\ti;\n}\n"},
+		{"for (;;) {}", "for (;;) {}\n"},
+		{"while (x) { break\ncontinue }", "while (x) {\n\tbreak;\n\tcontinue;\n}\n"},
+		{"do { x-- } while (x > 0) y", "do {\n\tx--;\n} while (x > 0);\ny;\n"},
+		{"for (; i;) { a }", "for (; i;) {\n\ta;\n}\n"},
+		{"try { a } catch (e) { try {} catch (f) { f } }",
+			"try {\n\ta;\n} catch (e) {\n\ttry {} catch (f) {\n\t\tf;\n\t}\n}\n"},
+		{"switch (x) { case 1: a; b case 2: default: c }",
+			"switch (x) {\ncase 1:\n\ta;\n\tb;\ncase 2:\ndefault:\n\tc;\n};\n"},
+		{"while (y) { let z = switch (x) { case 1: switch (y) {} } + 1 }",
+			"while (y) {\n\tlet z = switch (x) {\n\tcase 1:\n\t\tswitch (y) {};\n\t} + 1;\n}\n"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		actual, err := Source(tt.input)
+		if err != nil {
+			t.Fatalf("Source(%q) returned error: %s", tt.input, err)
+		}
+		if actual != tt.expected {
+			t.Errorf("Source(%q) wrong. expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+
+		// formatting is idempotent and keeps the program's meaning
+		again, err := Source(actual)
+		if err != nil {
+			t.Fatalf("Source(%q) returned error: %s", actual, err)
+		}
+		if again != actual {
+			t.Errorf("Source is not idempotent for %q. got=%q", actual, again)
+		}
+		if parsed(t, actual) != parsed(t, tt.input) {
+			t.Errorf("Source changed the program for %q. got=%q", tt.input, parsed(t, actual))
+		}
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	actual, err := Source("let = 5;\nlet x 5;")
+	if err == nil {
+		t.Fatalf("Source returned no error. got=%q", actual)
+	}
+	if actual != "" {
+		t.Errorf("Source returned output with an error. got=%q", actual)
+	}
+	expected := "1:5: expected next token to be IDENT, got = instead\n2:7: expected next token to be =, got INT instead"
+	if err.Error() != expected {
+		t.Errorf("wrong error. expected=%q, got=%q", expected, err.Error())
+	}
+}
+
+func TestSourceComments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"// comment\nx", "1:1: comments are not preserved by Source"},
+		{"x /* inline */", "1:3: comments are not preserved by Source"},
+		{"let x = 5;\nx // trailing", "2:3: comments are not preserved by Source"},
+	}
+
+	for _, tt := range tests {
+		actual, err := Source(tt.input)
+		if err == nil {
+			t.Fatalf("Source(%q) returned no error. got=%q", tt.input, actual)
+		}
+		if err.Error() != tt.expected {
+			t.Errorf("Source(%q) wrong error. expected=%q, got=%q", tt.input, tt.expected, err.Error())
+		}
+	}
+}
+
+// parsed returns the String of the program parsed from input
+func parsed(t *testing.T, input string) string {
+	program, errors := parser.Parse(input)
+	if len(errors) != 0 {
+		t.Fatalf("parser errors for %q: %q", input, errors)
+	}
+	return program.String()
+}
@@ -1,26 +1,89 @@
 package lexer
 
 import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/esquivias/interpreter/token"
 )
 
+// escapes maps the character following a backslash in a character literal to the character it represents
+var escapes = map[byte]string{
+	'0':  "\x00",
+	'n':  "\n",
+	'r':  "\r",
+	't':  "\t",
+	'\'': "'",
+	'\\': "\\",
+}
+
 // Lexer data structure
 type Lexer struct {
 	input        string
 	position     int  // current position in input (points to current char)
 	readPosition int  // current reading position in input (after current char)
 	ch           byte // current char under examination
+	line         int  // line of the current char, starting at 1
+	column       int  // column of the current char, starting at 1
+	suffixes     bool // read letters directly following a number as part of the number, e.g. 1k
+	comments     bool // emit comments as token.COMMENT instead of skipping them
+	unterminated bool // a /* was read with no */ after it, so no later /* can be closed either
 }
 
 // New returns a *Lexer
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
-	l.readChar() // initialize l.ch, l.position, and l.readPostion
+	l := &Lexer{}
+	l.Reset(input)
+	return l
+}
+
+// NewWithSuffixes returns a *Lexer that includes magnitude suffixes in integer literals (1k, 2M, 3G); the parser validates the suffix
+func NewWithSuffixes(input string) *Lexer {
+	l := New(input)
+	l.suffixes = true
 	return l
 }
 
+// NewWithComments returns a *Lexer that emits // and /* */ comments as token.COMMENT tokens for tools that preserve them
+func NewWithComments(input string) *Lexer {
+	l := New(input)
+	l.comments = true
+	return l
+}
+
+// Reset reinitializes the lexer to read input from the start so it can be reused; options such as suffixes are kept
+func (l *Lexer) Reset(input string) {
+	l.input = input
+	l.position = 0
+	l.readPosition = 0
+	l.ch = 0
+	l.line = 1
+	l.column = 0
+	l.unterminated = false
+	l.readChar() // initialize l.ch, l.position, l.readPostion, and l.column
+	l.skipShebang()
+}
+
+// skipShebang advances the lexer positions to the end of the first line if the input starts with #!, so scripts can be executable
+func (l *Lexer) skipShebang() {
+	if !strings.HasPrefix(l.input, "#!") {
+		return
+	}
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+}
+
 // readChar sets the next character and advances the position in the input string
 func (l *Lexer) readChar() {
+	// advance the line and column past the char being left behind
+	if l.ch == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -42,7 +105,8 @@ func (l *Lexer) peekChar() byte {
 // NextToken returns a token.Token data structure and advances the advances the lexer positions
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
-	l.skipWhitespace()
+	continued := l.skipWhitespace()
+	line, column, offset := l.line, l.column, l.position
 	switch l.ch {
 
 	//
@@ -51,88 +115,231 @@ func (l *Lexer) NextToken() token.Token {
 
 	case '=':
 		if l.peekChar() == '=' {
-			// Save the current l.ch in a local variable (ch) before calling l.readChar(), which will advance the lexer positions
-			ch := l.ch
 			l.readChar()
-			literal := string(ch) + string(l.ch)
+			literal := l.input[offset:l.readPosition]
 			tok = token.Token{Type: token.EQ, Literal: literal}
 		} else {
-			tok = newToken(token.ASSIGN, l.ch)
+			tok = l.newToken(token.ASSIGN)
 		}
 	case '+':
-		tok = newToken(token.PLUS, l.ch)
+		if l.peekChar() == '+' {
+			l.readChar()
+			literal := l.input[offset:l.readPosition]
+			tok = token.Token{Type: token.INC, Literal: literal}
+		} else {
+			tok = l.newToken(token.PLUS)
+		}
 	case '-':
-		tok = newToken(token.MINUS, l.ch)
+		if l.peekChar() == '-' {
+			l.readChar()
+			literal := l.input[offset:l.readPosition]
+			tok = token.Token{Type: token.DEC, Literal: literal}
+		} else {
+			tok = l.newToken(token.MINUS)
+		}
 	case '!':
 		if l.peekChar() == '=' {
-			// Save the current l.ch in a local variable (ch) before calling l.readChar(), which will advance the lexer positions
-			ch := l.ch
 			l.readChar()
-			literal := string(ch) + string(l.ch)
+			literal := l.input[offset:l.readPosition]
 			tok = token.Token{Type: token.NEQ, Literal: literal}
 		} else {
-			tok = newToken(token.BANG, l.ch)
+			tok = l.newToken(token.BANG)
 		}
 	case '*':
-		tok = newToken(token.ASTERISK, l.ch)
+		if l.peekChar() == '*' {
+			l.readChar()
+			literal := l.input[offset:l.readPosition]
+			tok = token.Token{Type: token.POW, Literal: literal}
+		} else {
+			tok = l.newToken(token.ASTERISK)
+		}
 	case '/':
-		tok = newToken(token.SLASH, l.ch)
+		// a terminated comment is only reached when comments are emitted; otherwise skipWhitespace skipped it
+		literal, ok := "", false
+		if l.atComment() {
+			literal, ok = l.readComment()
+		}
+		switch {
+		case ok:
+			tok = token.Token{Type: token.COMMENT, Literal: literal}
+		case l.peekChar() == '*':
+			// a /* without a closing */
+			l.readChar()
+			tok = token.Token{Type: token.ILLEGAL, Literal: l.input[offset:l.readPosition]}
+		default:
+			tok = l.newToken(token.SLASH)
+		}
+	case '?':
+		if l.peekChar() == '?' {
+			l.readChar()
+			literal := l.input[offset:l.readPosition]
+			tok = token.Token{Type: token.COALESCE, Literal: literal}
+		} else {
+			tok = l.newToken(token.ILLEGAL)
+		}
 	case '<':
-		tok = newToken(token.LT, l.ch)
+		tok = l.newToken(token.LT)
 	case '>':
-		tok = newToken(token.GT, l.ch)
+		tok = l.newToken(token.GT)
 
 	//
 	// Delimiters
 	//
 
+	case ':':
+		tok = l.newToken(token.COLON)
 	case ',':
-		tok = newToken(token.COMMA, l.ch)
+		tok = l.newToken(token.COMMA)
+	case '.':
+		tok = l.newToken(token.DOT)
 	case '{':
-		tok = newToken(token.LBRACE, l.ch)
+		tok = l.newToken(token.LBRACE)
 	case '(':
-		tok = newToken(token.LPAREN, l.ch)
+		tok = l.newToken(token.LPAREN)
 	case '}':
-		tok = newToken(token.RBRACE, l.ch)
+		tok = l.newToken(token.RBRACE)
 	case ')':
-		tok = newToken(token.RPAREN, l.ch)
+		tok = l.newToken(token.RPAREN)
 	case ';':
-		tok = newToken(token.SEMICOLON, l.ch)
+		tok = l.newToken(token.SEMICOLON)
+
+	//
+	// Literals
+	//
+
+	case '\'':
+		if literal, ok := l.readCharLiteral(); ok {
+			tok = token.Token{Type: token.CHAR, Literal: literal}
+		} else {
+			tok = token.Token{Type: token.ILLEGAL, Literal: literal}
+		}
 
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
 	default:
-		if isLetter(l.ch) {
+		if r, _ := l.currentRune(); isLetter(r) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
+			tok.Line, tok.Column, tok.Offset, tok.Continued = line, column, offset, continued
 			return tok
 		} else if isDigit(l.ch) {
 			tok.Type = token.INT
 			tok.Literal = l.readNumber()
+			tok.Line, tok.Column, tok.Offset, tok.Continued = line, column, offset, continued
 			return tok
 		} else {
-			tok = newToken(token.ILLEGAL, l.ch)
+			// keep every byte of a multi-byte character in a single token
+			_, size := l.currentRune()
+			for i := 1; i < size; i++ {
+				l.readChar()
+			}
+			tok = token.Token{Type: token.ILLEGAL, Literal: l.input[offset:l.readPosition]}
 		}
 	}
+	tok.Line, tok.Column, tok.Offset, tok.Continued = line, column, offset, continued
 	l.readChar()
 	return tok
 }
 
-// newToken returns a token.Token data structure
-func newToken(tokenType token.Type, ch byte) token.Token {
-	return token.Token{Type: tokenType, Literal: string(ch)}
+// newToken returns a token.Token data structure for the current char; the literal is sliced from the input to avoid an allocation
+func (l *Lexer) newToken(tokenType token.Type) token.Token {
+	return token.Token{Type: tokenType, Literal: l.input[l.position:l.readPosition]}
 }
 
-// skipWhitespace advances the lexer positions on space, tab, and newline
-func (l *Lexer) skipWhitespace() {
-	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+// skipWhitespace advances the lexer positions on space, tab, newline, a line continuation (a backslash ending the line),
+// and comments unless they are emitted; it returns true if every line break skipped was a line continuation
+func (l *Lexer) skipWhitespace() bool {
+	continued, newline := false, false
+	for {
+		switch {
+		case l.ch == '\n':
+			continued, newline = false, true
+			l.readChar()
+		case l.ch == ' ' || l.ch == '\t' || l.ch == '\r':
+			l.readChar()
+		case l.ch == '\\' && l.peekChar() == '\n':
+			// a continuation after a newline cannot join the lines the newline ended
+			continued = !newline
+			l.readChar()
+			l.readChar()
+		case l.ch == '\\' && strings.HasPrefix(l.input[l.readPosition:], "\r\n"):
+			continued = !newline
+			l.readChar()
+			l.readChar()
+			l.readChar()
+		case !l.comments && l.atComment():
+			if _, ok := l.readComment(); !ok {
+				return continued
+			}
+			l.readChar()
+		default:
+			return continued
+		}
+	}
+}
+
+// atComment returns true if a line comment or a block comment that may still be terminated starts at the current char
+func (l *Lexer) atComment() bool {
+	if l.ch != '/' {
+		return false
+	}
+	switch l.peekChar() {
+	case '/':
+		return true
+	case '*':
+		return !l.unterminated
+	}
+	return false
+}
+
+// readComment reads a // comment up to the end of the line or a /* */ comment and returns its source and true;
+// the lexer is left on the last char of the comment. For a /* without a closing */ it returns false and leaves the
+// lexer where it started, so the /* is read as an ILLEGAL token.
+func (l *Lexer) readComment() (string, bool) {
+	position := l.position
+	if l.peekChar() == '/' {
+		for l.peekChar() != '\n' && l.peekChar() != 0 {
+			l.readChar()
+		}
+		return l.input[position:l.readPosition], true
+	}
+	start := *l
+	// skip the '*' so /*/ is not read as a complete comment
+	l.readChar()
+	l.readChar()
+	for !(l.ch == '*' && l.peekChar() == '/') {
+		if l.ch == 0 {
+			*l = start
+			l.unterminated = true
+			return "", false
+		}
+		l.readChar()
+	}
+	l.readChar()
+	return l.input[position:l.readPosition], true
+}
+
+// currentRune returns the character starting at the current position, decoding multi-byte UTF-8, and its size in bytes
+func (l *Lexer) currentRune() (rune, int) {
+	return utf8.DecodeRuneInString(l.input[l.position:])
+}
+
+// readRune advances the lexer positions past every byte of the current character
+func (l *Lexer) readRune() {
+	_, size := l.currentRune()
+	for i := 0; i < size; i++ {
 		l.readChar()
 	}
 }
 
-// isLetter returns true or false
-func isLetter(ch byte) bool {
+// isLetter returns true or false; any Unicode letter or an underscore is a letter
+func isLetter(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+// isASCIILetter returns true for an ASCII letter or an underscore, the same as isLetter for chars below utf8.RuneSelf
+func isASCIILetter(ch byte) bool {
 	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
 }
 
@@ -141,20 +348,75 @@ func isDigit(ch byte) bool {
 	return '0' <= ch && ch <= '9'
 }
 
-// readIdentifier reads an identifier and advances the lexer positions until it encounters a non-letter-character
+// readIdentifier reads an identifier and advances the lexer positions until it encounters a non-letter-character;
+// digits are allowed after the first character
 func (l *Lexer) readIdentifier() string {
 	position := l.position
-	for isLetter(l.ch) {
-		l.readChar()
+	for {
+		// ASCII needs no decoding
+		if l.ch < utf8.RuneSelf {
+			if !isASCIILetter(l.ch) && !(l.position > position && isDigit(l.ch)) {
+				break
+			}
+			l.readChar()
+			continue
+		}
+		if r, _ := l.currentRune(); !isLetter(r) && !(l.position > position && unicode.IsDigit(r)) {
+			break
+		}
+		l.readRune()
 	}
 	return l.input[position:l.position]
 }
 
+// readCharLiteral reads a single-quoted character and returns it with escapes decoded;
+// for an empty literal, an unknown escape, or a missing closing quote it returns the source read so far and false.
+// The lexer is left on the last char of the literal.
+func (l *Lexer) readCharLiteral() (string, bool) {
+	position := l.position
+	switch l.peekChar() {
+	case '\'':
+		l.readChar()
+		return l.input[position:l.readPosition], false
+	case 0, '\n':
+		return l.input[position:l.readPosition], false
+	}
+	l.readChar()
+
+	var literal string
+	if l.ch == '\\' {
+		escaped, ok := escapes[l.peekChar()]
+		if !ok {
+			return l.input[position:l.readPosition], false
+		}
+		l.readChar()
+		literal = escaped
+	} else {
+		// read every byte of a multi-byte character
+		r, size := l.currentRune()
+		for i := 1; i < size; i++ {
+			l.readChar()
+		}
+		literal = string(r)
+	}
+
+	if l.peekChar() != '\'' {
+		return l.input[position:l.readPosition], false
+	}
+	l.readChar()
+	return literal, true
+}
+
 // readNumber reads a number and advances the lexer positions until it encounters a non-letter-character
 func (l *Lexer) readNumber() string {
 	position := l.position
 	for isDigit(l.ch) {
 		l.readChar()
 	}
+	if l.suffixes {
+		for r, _ := l.currentRune(); isLetter(r); r, _ = l.currentRune() {
+			l.readRune()
+		}
+	}
 	return l.input[position:l.position]
 }
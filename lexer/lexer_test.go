@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/esquivias/interpreter/token"
@@ -23,6 +24,12 @@ func TestNextToken(t *testing.T) {
 	}
 	10 == 10;
 	10 != 9;
+	i++;
+	i--;
+	2 ** 10;
+	a ?? b ? c;
+	obj.field;
+	let n: int = 1;
 	`
 	tests := []struct {
 		expectedType    token.Type
@@ -66,8 +73,7 @@ func TestNextToken(t *testing.T) {
 		{token.SEMICOLON, ";"},
 		{token.BANG, "!"},
 		{token.MINUS, "-"},
-		{token.SLASH, "/"},
-		{token.ASTERISK, "*"},
+		{token.ILLEGAL, "/*"},
 		{token.INT, "5"},
 		{token.SEMICOLON, ";"},
 		{token.INT, "5"},
@@ -101,6 +107,33 @@ func TestNextToken(t *testing.T) {
 		{token.NEQ, "!="},
 		{token.INT, "9"},
 		{token.SEMICOLON, ";"},
+		{token.IDENT, "i"},
+		{token.INC, "++"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "i"},
+		{token.DEC, "--"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "2"},
+		{token.POW, "**"},
+		{token.INT, "10"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "a"},
+		{token.COALESCE, "??"},
+		{token.IDENT, "b"},
+		{token.ILLEGAL, "?"},
+		{token.IDENT, "c"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "obj"},
+		{token.DOT, "."},
+		{token.IDENT, "field"},
+		{token.SEMICOLON, ";"},
+		{token.LET, "let"},
+		{token.IDENT, "n"},
+		{token.COLON, ":"},
+		{token.IDENT, "int"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
 		{token.EOF, ""},
 	}
 
@@ -120,3 +153,497 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "let five = 5;\n  five == 10;\n"
+
+	tests := []struct {
+		expectedType   token.Type
+		expectedLine   int
+		expectedColumn int
+	}{
+		{token.LET, 1, 1},
+		{token.IDENT, 1, 5},
+		{token.ASSIGN, 1, 10},
+		{token.INT, 1, 12},
+		{token.SEMICOLON, 1, 13},
+		{token.IDENT, 2, 3},
+		{token.EQ, 2, 8},
+		{token.INT, 2, 11},
+		{token.SEMICOLON, 2, 13},
+		{token.EOF, 3, 1},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Errorf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestTokenOffsets(t *testing.T) {
+	input := "let add = fn(x, y) {\n\tx + y;\n};"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedOffset  int
+	}{
+		{"let", 0},
+		{"add", 4},
+		{"=", 8},
+		{"fn", 10},
+		{"(", 12},
+		{"x", 13},
+		{",", 14},
+		{"y", 16},
+		{")", 17},
+		{"{", 19},
+		{"x", 22},
+		{"+", 24},
+		{"y", 26},
+		{";", 27},
+		{"}", 29},
+		{";", 30},
+		{"", 31},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Offset != tt.expectedOffset {
+			t.Errorf("tests[%d] - offset wrong. expected=%d, got=%d",
+				i, tt.expectedOffset, tok.Offset)
+		}
+
+		if tok.Type != token.EOF && input[tok.Offset:tok.Offset+len(tok.Literal)] != tok.Literal {
+			t.Errorf("tests[%d] - input at offset is not the literal. got=%q",
+				i, input[tok.Offset:tok.Offset+len(tok.Literal)])
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := "a // line\n/* block\n */ b / c /* end */"
+
+	tests := []struct {
+		input    string
+		comments bool
+		expected []token.Token
+	}{
+		{
+			input,
+			false,
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.IDENT, Literal: "b", Line: 3, Column: 5, Offset: 23},
+				{Type: token.SLASH, Literal: "/", Line: 3, Column: 7, Offset: 25},
+				{Type: token.IDENT, Literal: "c", Line: 3, Column: 9, Offset: 27},
+				{Type: token.EOF, Literal: "", Line: 3, Column: 20, Offset: 38},
+			},
+		},
+		{
+			input,
+			true,
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.COMMENT, Literal: "// line", Line: 1, Column: 3, Offset: 2},
+				{Type: token.COMMENT, Literal: "/* block\n */", Line: 2, Column: 1, Offset: 10},
+				{Type: token.IDENT, Literal: "b", Line: 3, Column: 5, Offset: 23},
+				{Type: token.SLASH, Literal: "/", Line: 3, Column: 7, Offset: 25},
+				{Type: token.IDENT, Literal: "c", Line: 3, Column: 9, Offset: 27},
+				{Type: token.COMMENT, Literal: "/* end */", Line: 3, Column: 11, Offset: 29},
+				{Type: token.EOF, Literal: "", Line: 3, Column: 20, Offset: 38},
+			},
+		},
+		{
+			// without a closing */ there is no comment
+			"a /* b",
+			true,
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.ILLEGAL, Literal: "/*", Line: 1, Column: 3, Offset: 2},
+				{Type: token.IDENT, Literal: "b", Line: 1, Column: 6, Offset: 5},
+			},
+		},
+		{
+			"a /* b\n/* c / d",
+			false,
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.ILLEGAL, Literal: "/*", Line: 1, Column: 3, Offset: 2},
+				{Type: token.IDENT, Literal: "b", Line: 1, Column: 6, Offset: 5},
+				{Type: token.ILLEGAL, Literal: "/*", Line: 2, Column: 1, Offset: 7},
+				{Type: token.IDENT, Literal: "c", Line: 2, Column: 4, Offset: 10},
+				{Type: token.SLASH, Literal: "/", Line: 2, Column: 6, Offset: 12},
+				{Type: token.IDENT, Literal: "d", Line: 2, Column: 8, Offset: 14},
+				{Type: token.EOF, Literal: "", Line: 2, Column: 9, Offset: 15},
+			},
+		},
+		{
+			"/*/ */",
+			true,
+			[]token.Token{
+				{Type: token.COMMENT, Literal: "/*/ */", Line: 1, Column: 1, Offset: 0},
+				{Type: token.EOF, Literal: "", Line: 1, Column: 7, Offset: 6},
+			},
+		},
+		{
+			"//",
+			true,
+			[]token.Token{
+				{Type: token.COMMENT, Literal: "//", Line: 1, Column: 1, Offset: 0},
+				{Type: token.EOF, Literal: "", Line: 1, Column: 3, Offset: 2},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		if tt.comments {
+			l = NewWithComments(tt.input)
+		}
+
+		for j, expected := range tt.expected {
+			tok := l.NextToken()
+			if tok != expected {
+				t.Fatalf("tests[%d][%d] - token wrong. expected=%+v, got=%+v", i, j, expected, tok)
+			}
+		}
+	}
+}
+
+func TestNumberSuffixes(t *testing.T) {
+	input := "1k 2M 3G 4kb 5 k"
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.INT, "1k"},
+		{token.INT, "2M"},
+		{token.INT, "3G"},
+		{token.INT, "4kb"},
+		{token.INT, "5"},
+		{token.IDENT, "k"},
+		{token.EOF, ""},
+	}
+
+	l := NewWithSuffixes(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+
+	// suffixes are opt-in; by default a letter ends the number
+	l = New("1k")
+	if tok := l.NextToken(); tok.Type != token.INT || tok.Literal != "1" {
+		t.Fatalf("default lexer read a suffix. got=%+v", tok)
+	}
+	if tok := l.NextToken(); tok.Type != token.IDENT || tok.Literal != "k" {
+		t.Fatalf("default lexer did not read the suffix as an identifier. got=%+v", tok)
+	}
+}
+
+func TestReset(t *testing.T) {
+	first := "let five = 5;\nfive != 10;"
+	second := "let add = x + y;\n\t!-/*5 ** 2;\nnull ?? 1;"
+
+	l := New(first)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+	}
+
+	l.Reset(second)
+	fresh := New(second)
+
+	for i := 0; ; i++ {
+		expected := fresh.NextToken()
+		tok := l.NextToken()
+
+		if tok != expected {
+			t.Fatalf("tokens[%d] - reset lexer differs. expected=%+v, got=%+v",
+				i, expected, tok)
+		}
+
+		if expected.Type == token.EOF {
+			break
+		}
+	}
+}
+
+func TestCharLiterals(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.Type
+		expectedLiteral string
+		expectedNext    token.Type
+	}{
+		{`'a';`, token.CHAR, "a", token.SEMICOLON},
+		{`'\n';`, token.CHAR, "\n", token.SEMICOLON},
+		{`'\'';`, token.CHAR, "'", token.SEMICOLON},
+		{`'\\';`, token.CHAR, "\\", token.SEMICOLON},
+		{`'\0';`, token.CHAR, "\x00", token.SEMICOLON},
+		{`'é';`, token.CHAR, "é", token.SEMICOLON},
+		{`'';`, token.ILLEGAL, "''", token.SEMICOLON},
+		{`'ab';`, token.ILLEGAL, "'a", token.IDENT},
+		{`'\q';`, token.ILLEGAL, `'\`, token.IDENT},
+		{"'\n;", token.ILLEGAL, "'", token.SEMICOLON},
+		{`'`, token.ILLEGAL, "'", token.EOF},
+		{`'a`, token.ILLEGAL, "'a", token.EOF},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if next := l.NextToken(); next.Type != tt.expectedNext {
+			t.Fatalf("tests[%d] - next tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedNext, next.Type)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := "let café = naïve_2 + x1; π € 名前;"
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+		expectedOffset  int
+	}{
+		{token.LET, "let", 0},
+		{token.IDENT, "café", 4},
+		{token.ASSIGN, "=", 10},
+		{token.IDENT, "naïve_2", 12},
+		{token.PLUS, "+", 21},
+		{token.IDENT, "x1", 23},
+		{token.SEMICOLON, ";", 25},
+		{token.IDENT, "π", 27},
+		{token.ILLEGAL, "€", 30},
+		{token.IDENT, "名前", 34},
+		{token.SEMICOLON, ";", 40},
+		{token.EOF, "", 41},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Offset != tt.expectedOffset {
+			t.Errorf("tests[%d] - offset wrong. expected=%d, got=%d",
+				i, tt.expectedOffset, tok.Offset)
+		}
+	}
+}
+
+func TestLineContinuation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []token.Token
+	}{
+		{
+			"a \\\n+ b",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.PLUS, Literal: "+", Line: 2, Column: 1, Offset: 4, Continued: true},
+				{Type: token.IDENT, Literal: "b", Line: 2, Column: 3, Offset: 6},
+			},
+		},
+		{
+			"a\\\r\nb",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.IDENT, Literal: "b", Line: 2, Column: 1, Offset: 4, Continued: true},
+			},
+		},
+		{
+			// a newline after the continuation ends the logical line
+			"a \\\n\nb",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.IDENT, Literal: "b", Line: 3, Column: 1, Offset: 5},
+			},
+		},
+		{
+			// and so does a newline before it
+			"x\n\\\n- y",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x", Line: 1, Column: 1, Offset: 0},
+				{Type: token.MINUS, Literal: "-", Line: 3, Column: 1, Offset: 4},
+				{Type: token.IDENT, Literal: "y", Line: 3, Column: 3, Offset: 6},
+			},
+		},
+		{
+			"a \\ b\\",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "a", Line: 1, Column: 1, Offset: 0},
+				{Type: token.ILLEGAL, Literal: "\\", Line: 1, Column: 3, Offset: 2},
+				{Type: token.IDENT, Literal: "b", Line: 1, Column: 5, Offset: 4},
+				{Type: token.ILLEGAL, Literal: "\\", Line: 1, Column: 6, Offset: 5},
+				{Type: token.EOF, Literal: "", Line: 1, Column: 7, Offset: 6},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+
+		for j, expected := range tt.expected {
+			tok := l.NextToken()
+			if tok != expected {
+				t.Fatalf("tests[%d][%d] - token wrong. expected=%+v, got=%+v", i, j, expected, tok)
+			}
+		}
+	}
+}
+
+func TestShebang(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []token.Token
+	}{
+		{
+			"#!/usr/bin/env monkey\nlet x = 5;",
+			[]token.Token{
+				{Type: token.LET, Literal: "let", Line: 2, Column: 1, Offset: 22},
+				{Type: token.IDENT, Literal: "x", Line: 2, Column: 5, Offset: 26},
+			},
+		},
+		{
+			"#!/usr/bin/env monkey",
+			[]token.Token{
+				{Type: token.EOF, Literal: "", Line: 1, Column: 22, Offset: 21},
+			},
+		},
+		{
+			" #!x",
+			[]token.Token{
+				{Type: token.ILLEGAL, Literal: "#", Line: 1, Column: 2, Offset: 1},
+				{Type: token.BANG, Literal: "!", Line: 1, Column: 3, Offset: 2},
+				{Type: token.IDENT, Literal: "x", Line: 1, Column: 4, Offset: 3},
+			},
+		},
+		{
+			"x\n#!x",
+			[]token.Token{
+				{Type: token.IDENT, Literal: "x", Line: 1, Column: 1, Offset: 0},
+				{Type: token.ILLEGAL, Literal: "#", Line: 2, Column: 1, Offset: 2},
+				{Type: token.BANG, Literal: "!", Line: 2, Column: 2, Offset: 3},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+
+		for j, expected := range tt.expected {
+			tok := l.NextToken()
+			if tok != expected {
+				t.Fatalf("tests[%d][%d] - token wrong. expected=%+v, got=%+v",
+					i, j, expected, tok)
+			}
+		}
+	}
+}
+
+// benchmarkInput is a representative program repeated to about 100KB
+var benchmarkInput = strings.Repeat(`let fibonacci = fn(n) {
+	// recursive, to exercise identifiers and operators
+	let result = n if n < 2 else fibonacci(n - 1) + fibonacci(n - 2);
+	return result;
+};
+let total = 0;
+for (let i = 0; i < 1000; i++) {
+	total = total + fibonacci(i) * 2 ** 3 / 4;
+	/* member access and coalescing */
+	total = config.limit ?? total;
+}
+while (total != 0) { total--; }
+let c = '\n';
+`, 300)
+
+func BenchmarkNextToken(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkInput)))
+	l := New("")
+	for i := 0; i < b.N; i++ {
+		l.Reset(benchmarkInput)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+	}
+}
+
+// identifierInput repeats a few identifiers and keywords many times
+var identifierInput = strings.Repeat("let total = total + count; if counter else total; return total;\n", 2000)
+
+func BenchmarkIdentifiers(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(identifierInput)))
+	l := New("")
+	for i := 0; i < b.N; i++ {
+		l.Reset(identifierInput)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+	}
+}
+
+func TestNextTokenAllocations(t *testing.T) {
+	// literals, including repeated identifiers and keywords, share the input's storage instead of being copied
+	input := benchmarkInput + identifierInput
+	l := New("")
+	allocs := testing.AllocsPerRun(10, func() {
+		l.Reset(input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("lexing allocated. got=%v allocs per run", allocs)
+	}
+}
@@ -9,6 +9,15 @@ import (
 )
 
 func main() {
+	// run a file given as the first argument instead of starting the REPL; diagnostics go to stderr
+	if len(os.Args) > 1 {
+		if err := repl.RunFile(os.Args[1], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
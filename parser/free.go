@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"github.com/esquivias/interpreter/ast"
+)
+
+// FreeVariables returns the identifiers used but not bound within node, in order of first use
+func FreeVariables(node ast.Node) []string {
+	fv := &freeVariables{
+		scopes: []map[string]bool{{}},
+		seen:   map[string]bool{},
+		names:  []string{},
+	}
+	fv.walk(node)
+	return fv.names
+}
+
+// freeVariables tracks the bindings in scope while walking an AST
+type freeVariables struct {
+	scopes []map[string]bool // innermost scope last
+	seen   map[string]bool   // free identifiers already recorded
+	names  []string          // free identifiers in order of first use
+}
+
+// walk visits node and its children in evaluation order
+func (fv *freeVariables) walk(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.Program:
+		for _, s := range node.Statements {
+			fv.walk(s)
+		}
+	case *ast.BlockStatement:
+		fv.push()
+		for _, s := range node.Statements {
+			fv.walk(s)
+		}
+		fv.pop()
+	case *ast.LetStatement:
+		// names chained in the value (let a = b = 5;) are bound along with the let's own name
+		names := []string{node.Name.Value}
+		value := node.Value
+		for assign, ok := value.(*ast.AssignExpression); ok; assign, ok = value.(*ast.AssignExpression) {
+			names = append(names, assign.Name.Value)
+			value = assign.Value
+		}
+		// the value is walked first, so let x = x; uses a free x
+		if value != nil {
+			fv.walk(value)
+		}
+		for _, name := range names {
+			fv.bind(name)
+		}
+	case *ast.ReturnStatement:
+		if node.ReturnValue != nil {
+			fv.walk(node.ReturnValue)
+		}
+	case *ast.ExpressionStatement:
+		if node.Expression != nil {
+			fv.walk(node.Expression)
+		}
+	case *ast.WhileStatement:
+		fv.walk(node.Condition)
+		fv.walk(node.Body)
+	case *ast.DoWhileStatement:
+		// bindings in the body are not visible to the condition
+		fv.walk(node.Body)
+		fv.walk(node.Condition)
+	case *ast.ForStatement:
+		// a binding in the init section is only visible to the rest of the loop
+		fv.push()
+		if node.Init != nil {
+			fv.walk(node.Init)
+		}
+		if node.Condition != nil {
+			fv.walk(node.Condition)
+		}
+		if node.Post != nil {
+			fv.walk(node.Post)
+		}
+		fv.walk(node.Body)
+		fv.pop()
+	case *ast.TryStatement:
+		fv.walk(node.Body)
+		// the catch parameter is only bound within the catch block
+		fv.push()
+		fv.bind(node.Parameter.Value)
+		fv.walk(node.Catch)
+		fv.pop()
+	case *ast.IfExpression:
+		// the condition is evaluated first
+		fv.walk(node.Condition)
+		fv.walk(node.Consequence)
+		fv.walk(node.Alternative)
+	case *ast.PrefixExpression:
+		fv.walk(node.Right)
+	case *ast.InfixExpression:
+		fv.walk(node.Left)
+		fv.walk(node.Right)
+	case *ast.PostfixExpression:
+		fv.walk(node.Left)
+	case *ast.AssignExpression:
+		// outside a let, assignment updates an existing binding
+		fv.walk(node.Value)
+		fv.use(node.Name.Value)
+	case *ast.SwitchExpression:
+		fv.walk(node.Subject)
+		for _, c := range node.Cases {
+			fv.walk(c.Value)
+			fv.walk(c.Body)
+		}
+		if node.Default != nil {
+			fv.walk(node.Default)
+		}
+	case *ast.MemberExpression:
+		// the property names a hash key, not a binding
+		fv.walk(node.Object)
+	case *ast.Identifier:
+		fv.use(node.Value)
+	}
+}
+
+// push opens a new innermost scope
+func (fv *freeVariables) push() {
+	fv.scopes = append(fv.scopes, map[string]bool{})
+}
+
+// pop closes the innermost scope
+func (fv *freeVariables) pop() {
+	fv.scopes = fv.scopes[:len(fv.scopes)-1]
+}
+
+// bind adds name to the innermost scope
+func (fv *freeVariables) bind(name string) {
+	fv.scopes[len(fv.scopes)-1][name] = true
+}
+
+// use records name as free unless it is bound in an enclosing scope
+func (fv *freeVariables) use(name string) {
+	for i := len(fv.scopes) - 1; i >= 0; i-- {
+		if fv.scopes[i][name] {
+			return
+		}
+	}
+	if !fv.seen[name] {
+		fv.seen[name] = true
+		fv.names = append(fv.names, name)
+	}
+}
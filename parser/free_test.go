@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/esquivias/interpreter/lexer"
+)
+
+func TestFreeVariables(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"a + b * a;", []string{"a", "b"}},
+		{"let x = 5; x + y;", []string{"y"}},
+		{"let x = x + 1;", []string{"x"}},
+		{"let x; x + y;", []string{"y"}},
+		{"x; let x = 1; x;", []string{"x"}},
+		{"let a = 1; let b = a * 2; -b ** a;", []string{}},
+		// bindings inside a block do not escape it, outer bindings are visible inside
+		{"let n = 1; while (n < max) { let m = n; m++; } m;", []string{"max", "m"}},
+		{"for (let i = 0; i < n; i++) { total + i; } i;", []string{"n", "total", "i"}},
+		{"a if b else c ?? d;", []string{"b", "a", "c", "d"}},
+		{"try { a; } catch (e) { e + b; } e;", []string{"a", "b", "e"}},
+		{"let o = 1; o.a + p.b;", []string{"p"}},
+		{"let a = b = c; a + b;", []string{"c"}},
+		{"a = b = 1;", []string{"b", "a"}},
+		{"do { let x = 1; y; } while (x);", []string{"y", "x"}},
+		{"let a = 1; switch (a) { case b: let c = a; c; default: c; }", []string{"b", "c"}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := FreeVariables(program)
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("FreeVariables(%q) wrong. expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
@@ -2,7 +2,11 @@ package parser
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/esquivias/interpreter/ast"
 	"github.com/esquivias/interpreter/lexer"
@@ -13,6 +17,12 @@ const (
 	_ int = iota
 	// LOWEST nil (no parse)
 	LOWEST
+	// ASSIGNMENT X = Y
+	ASSIGNMENT
+	// CONDITIONAL X if Y else Z; binds looser than every operator but assignment, so a + 1 if c else b * 2 is (a + 1) if c else (b * 2)
+	CONDITIONAL
+	// COALESCE X ?? Y
+	COALESCE
 	// EQUALS ==
 	EQUALS
 	// LESSGREATER > or <
@@ -21,48 +31,123 @@ const (
 	SUM
 	// PRODUCT *
 	PRODUCT
-	// PREFIX -X or !X
+	// POW **
+	POW
+	// PREFIX -X or !X; the operand of - is parsed just below POW instead
 	PREFIX
+	// POSTFIX X++ or X--
+	POSTFIX
 	// CALL myFunction(X)
 	CALL
 )
 
-var precedences = map[token.Type]int{
-	token.EQ:       EQUALS,
-	token.NEQ:      EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
+// precedence returns the precedence of an infix or postfix operator token type, or LOWEST for any other token;
+// it is a switch rather than a map because it runs for every token parsed
+func precedence(t token.Type) int {
+	switch t {
+	case token.ASSIGN:
+		return ASSIGNMENT
+	case token.IF:
+		return CONDITIONAL
+	case token.COALESCE:
+		return COALESCE
+	case token.EQ, token.NEQ:
+		return EQUALS
+	case token.LT, token.GT:
+		return LESSGREATER
+	case token.PLUS, token.MINUS:
+		return SUM
+	case token.SLASH, token.ASTERISK:
+		return PRODUCT
+	case token.POW:
+		return POW
+	case token.INC, token.DEC, token.BANG:
+		return POSTFIX
+	case token.DOT:
+		return CALL
+	}
+	return LOWEST
+}
+
+// ParseError struct describes a parse error and the position of the token that caused it
+type ParseError struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+// Error returns the parse error message prefixed with its line and column
+func (pe ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", pe.Line, pe.Column, pe.Message)
+}
+
+// magnitudes are the multipliers of the integer literal suffixes read by lexer.NewWithSuffixes
+var magnitudes = map[string]int64{
+	"k": 1000,
+	"M": 1000 * 1000,
+	"G": 1000 * 1000 * 1000,
+	"T": 1000 * 1000 * 1000 * 1000,
+}
+
+// statementKeywords are the token types that begin a statement
+var statementKeywords = map[token.Type]bool{
+	token.LET:      true,
+	token.RETURN:   true,
+	token.BREAK:    true,
+	token.CONTINUE: true,
+	token.WHILE:    true,
+	token.DO:       true,
+	token.FOR:      true,
+	token.TRY:      true,
+}
+
+// rightAssociative are the infix token types that group right-to-left, e.g. a ** b ** c is a ** (b ** c)
+var rightAssociative = map[token.Type]bool{
+	token.ASSIGN:   true,
+	token.POW:      true,
+	token.COALESCE: true,
+}
+
+// relational are the comparison token types that cannot be chained, e.g. 1 < x < 10
+var relational = map[token.Type]bool{
+	token.LT: true,
+	token.GT: true,
 }
 
 // Parser struct
 type Parser struct {
-	l              *lexer.Lexer // pointer to an instance of the lexer (NextToken())
-	curToken       token.Token
-	peekToken      token.Token
-	errors         []string
-	prefixParseFns map[token.Type]prefixParseFn
-	infixParseFns  map[token.Type]infixParseFn
+	l               *lexer.Lexer // pointer to an instance of the lexer (NextToken())
+	curToken        token.Token
+	peekToken       token.Token
+	peek2Token      token.Token   // the token after peekToken
+	parens          int           // depth of the parenthesized headers being parsed, where newlines do not end anything
+	tokens          []token.Token // every token read from the lexer, so the parser can rewind
+	position        int           // index in tokens of the next token to become peek2Token
+	errors          []ParseError
+	prefixParseFns  map[token.Type]prefixParseFn
+	infixParseFns   map[token.Type]infixParseFn
+	postfixParseFns map[token.Type]postfixParseFn
 }
 
 type (
-	prefixParseFn func() ast.Expression
-	infixParseFn  func(ast.Expression) ast.Expression
+	prefixParseFn  func() ast.Expression
+	infixParseFn   func(ast.Expression) ast.Expression
+	postfixParseFn func(ast.Expression) ast.Expression
 )
 
 // New Parser returns a Parser struct with a lexer and tokens set.
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
 		l:      l,
-		errors: []string{},
+		errors: []ParseError{},
 	}
 	//
 	p.prefixParseFns = make(map[token.Type]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.CHAR, p.parseCharLiteral)
+	p.registerPrefix(token.NULL, p.parseNullLiteral)
+	p.registerPrefix(token.SWITCH, p.parseSwitchExpression)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	//
@@ -71,12 +156,24 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.POW, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NEQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.COALESCE, p.parseInfixExpression)
+	p.registerInfix(token.IF, p.parseConditionalExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
+	//
+	p.postfixParseFns = make(map[token.Type]postfixParseFn)
+	p.registerPostfix(token.INC, p.parsePostfixExpression)
+	p.registerPostfix(token.DEC, p.parsePostfixExpression)
+	// '!' after an operand is factorial; at the start of an expression it is the prefix logical not
+	p.registerPostfix(token.BANG, p.parsePostfixExpression)
 
-	// Read two tokens so both curToken and peekToken are set
+	// Read three tokens so curToken, peekToken, and peek2Token are set; every mark is at least 3
+	p.nextToken()
 	p.nextToken()
 	p.nextToken()
 	return p
@@ -86,15 +183,61 @@ func New(l *lexer.Lexer) *Parser {
 func (p *Parser) ParseProgram() *ast.Program {
 	program := &ast.Program{} // construct the root node of the AST
 	program.Statements = []ast.Statement{}
+	p.ParseEach(func(stmt ast.Statement) {
+		program.Statements = append(program.Statements, stmt)
+	})
+	return program
+}
+
+// ParseEach calls f with each top-level statement as soon as it is parsed, so a large program does not have to be held
+// in memory at once; the parser errors are available from Errors() once it returns
+func (p *Parser) ParseEach(f func(ast.Statement)) {
 	// iterate over every token in the input until an token.EOF token is encountered
 	for !p.curTokenIs(token.EOF) {
+		errors := len(p.errors)
 		stmt := p.parseStatement()
 		if stmt != nil {
-			program.Statements = append(program.Statements, stmt)
+			f(stmt)
+		}
+		// recover from a malformed statement before parsing the next one
+		if len(p.errors) > errors {
+			p.synchronize()
+		}
+		p.nextToken()
+		p.discardTokens()
+	}
+}
+
+// Parse parses input as a program and returns it with the parser errors
+func Parse(input string) (*ast.Program, []string) {
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	return program, p.Errors()
+}
+
+// ParseExpression parses input as a single expression, optionally followed by a semicolon, and returns it with the parser errors
+func ParseExpression(input string) (ast.Expression, []string) {
+	p := New(lexer.New(input))
+	expression := p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	if len(p.errors) == 0 && !p.peekTokenIs(token.EOF) {
+		msg := fmt.Sprintf("expected end of expression, got %s instead", p.peekToken.Type)
+		p.addError(p.peekToken, msg)
+	}
+	return expression, p.Errors()
+}
+
+// synchronize advances the tokens after a parse error until the current token ends a statement or a block (a nested
+// block has already recovered), the peek token begins a new statement, or the peek token closes the enclosing block
+func (p *Parser) synchronize() {
+	for !p.curTokenIs(token.SEMICOLON) && !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		if statementKeywords[p.peekToken.Type] || p.peekTokenIs(token.RBRACE) || p.peekTokenOnNewLine() {
+			return
 		}
 		p.nextToken()
 	}
-	return program
 }
 
 // parseIdentifier
@@ -106,12 +249,39 @@ func (p *Parser) parseIdentifier() ast.Expression {
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		// avoid returning a typed nil (*ast.LetStatement) as a non-nil ast.Statement
+		if stmt := p.parseLetStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
+	case token.WHILE:
+		if stmt := p.parseWhileStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.DO:
+		if stmt := p.parseDoWhileStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.FOR:
+		if stmt := p.parseForStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.TRY:
+		if stmt := p.parseTryStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	default:
-		// The only two real statement types are let and return statements.
-		// We try to parse expression statements if we don't encounter one of the other two.
+		// We try to parse expression statements if we don't encounter a statement keyword.
 		return p.parseExpressionStatement()
 	}
 }
@@ -123,12 +293,27 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		return nil
 	}
 	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	// an optional type annotation; it is kept for tooling and ignored by evaluation
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Type = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+	// a declaration without an initializer, ended like a bare return, leaves Value nil
+	if p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.RBRACE) || p.peekTokenIs(token.EOF) || p.peekTokenOnNewLine() {
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+		return stmt
+	}
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
-	// TODO: We're skipping the expressions until we encounter a semicolon
-	// NOTE: A missing semicolon will cause a timeout
-	for !p.curTokenIs(token.SEMICOLON) {
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 	return stmt
@@ -137,16 +322,186 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 // parseReturnStatement function
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
+	// a bare return, ended by a semicolon, a closing brace, the end of input, or a new line, leaves ReturnValue nil
+	if p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.RBRACE) || p.peekTokenIs(token.EOF) || p.peekTokenOnNewLine() {
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+		return stmt
+	}
 	p.nextToken()
+	stmt.ReturnValue = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseBreakStatement returns a BREAK Statement AST Node
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{Token: p.curToken}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseContinueStatement returns a CONTINUE Statement AST Node
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{Token: p.curToken}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// parseWhileStatement returns a WHILE Statement AST Node
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	stmt := &ast.WhileStatement{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	p.parens++
+	stmt.Condition = p.parseExpression(LOWEST)
+	p.parens--
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+	return stmt
+}
 
-	// TODO: We're skipping the expressions until we encounter a semicolon
-	for !p.curTokenIs(token.SEMICOLON) {
+// parseDoWhileStatement returns a DO WHILE Statement AST Node
+func (p *Parser) parseDoWhileStatement() *ast.DoWhileStatement {
+	stmt := &ast.DoWhileStatement{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+	if !p.expectPeek(token.WHILE) {
+		return nil
+	}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	p.parens++
+	stmt.Condition = p.parseExpression(LOWEST)
+	p.parens--
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
+	return stmt
+}
+
+// parseForStatement returns a FOR Statement AST Node; each of the init, condition, and post sections may be empty
+func (p *Parser) parseForStatement() *ast.ForStatement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	// the body is a block, which resets the depth for itself
+	p.parens++
+	defer func() { p.parens-- }()
+	p.nextToken()
+	// only a let statement or an expression statement can initialize the loop; any other is still parsed to carry on
+	if statementKeywords[p.curToken.Type] && !p.curTokenIs(token.LET) {
+		msg := fmt.Sprintf("unexpected %s in for initializer", p.curToken.Literal)
+		p.addError(p.curToken, msg)
+	}
+	if !p.curTokenIs(token.SEMICOLON) {
+		stmt.Init = p.parseStatement()
+		// let statements and expression statements followed by a semicolon stop on the token.SEMICOLON
+		if !p.curTokenIs(token.SEMICOLON) && !p.expectPeek(token.SEMICOLON) {
+			return nil
+		}
+	}
+	p.nextToken()
+	if !p.curTokenIs(token.SEMICOLON) {
+		stmt.Condition = p.parseExpression(LOWEST)
+		if !p.expectPeek(token.SEMICOLON) {
+			return nil
+		}
+	}
+	p.nextToken()
+	if !p.curTokenIs(token.RPAREN) {
+		stmt.Post = p.parseExpression(LOWEST)
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+	return stmt
+}
 
+// parseTryStatement returns a TRY Statement AST Node
+func (p *Parser) parseTryStatement() *ast.TryStatement {
+	stmt := &ast.TryStatement{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+	if !p.expectPeek(token.CATCH) {
+		return nil
+	}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Parameter = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Catch = p.parseBlockStatement()
 	return stmt
 }
 
+// parseBlockStatement parses statements until the closing token.RBRACE (or token.EOF) is encountered
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken}
+	block.Statements = []ast.Statement{}
+	// newlines end statements again inside a block, even one within a header such as a switch in a condition
+	parens := p.parens
+	p.parens = 0
+	p.nextToken()
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		errors := len(p.errors)
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		if len(p.errors) > errors {
+			// a statement that failed on the closing brace has not consumed it
+			last := p.errors[len(p.errors)-1]
+			if p.curTokenIs(token.RBRACE) && last.Line == p.curToken.Line && last.Column == p.curToken.Column {
+				break
+			}
+			p.synchronize()
+		}
+		p.nextToken()
+	}
+	if p.curTokenIs(token.EOF) {
+		p.addError(p.curToken, "expected }, got EOF instead")
+	}
+	p.parens = parens
+	return block
+}
+
 // parseExpressionStatement
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
@@ -169,7 +524,14 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	}
 	leftExp := prefix()
 
-	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+	// a line break ends the expression unless the line ends with an operator, like a semicolon would
+	for !p.peekTokenIs(token.SEMICOLON) && !p.peekTokenOnNewLine() && precedence < p.peekPrecedence() {
+		if postfix := p.postfixParseFns[p.peekToken.Type]; postfix != nil {
+			p.nextToken()
+			leftExp = postfix(leftExp)
+			continue
+		}
+
 		infix := p.infixParseFns[p.peekToken.Type]
 		if infix == nil {
 			return leftExp
@@ -185,28 +547,73 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 // parseIntegerLiteral
 func (p *Parser) parseIntegerLiteral() ast.Expression {
-	lit := &ast.IntegerLiteral{Token: p.curToken}
+	return p.integerLiteral(p.curToken)
+}
 
-	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
-	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+// integerLiteral returns the IntegerLiteral for an INT token, whose literal may start with the minus of a negative literal
+func (p *Parser) integerLiteral(tok token.Token) ast.Expression {
+	lit := &ast.IntegerLiteral{Token: tok}
+
+	// split a magnitude suffix (e.g. the k in 1k) from the digits
+	digits := strings.TrimRightFunc(tok.Literal, unicode.IsLetter)
+	multiplier := int64(1)
+	if suffix := tok.Literal[len(digits):]; suffix != "" {
+		m, ok := magnitudes[suffix]
+		if !ok {
+			msg := fmt.Sprintf("unknown integer suffix %q in %q", suffix, tok.Literal)
+			p.addError(tok, msg)
+			return nil
+		}
+		multiplier = m
+	}
+
+	value, err := strconv.ParseInt(digits, 0, 64)
+	if err != nil || value > math.MaxInt64/multiplier || value < math.MinInt64/multiplier {
+		msg := fmt.Sprintf("could not parse %q as integer", tok.Literal)
+		p.addError(tok, msg)
 		return nil
 	}
 
-	lit.Value = value
+	lit.Value = value * multiplier
 
 	return lit
 }
 
+// parseCharLiteral
+func (p *Parser) parseCharLiteral() ast.Expression {
+	value, _ := utf8.DecodeRuneInString(p.curToken.Literal)
+	return &ast.CharLiteral{Token: p.curToken, Value: value}
+}
+
+// parseNullLiteral
+func (p *Parser) parseNullLiteral() ast.Expression {
+	return &ast.NullLiteral{Token: p.curToken}
+}
+
 // parsePrefixExpression
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{
 		Token:    p.curToken,
 		Operator: p.curToken.Literal,
+		OpType:   p.curToken.Type,
 	}
 	p.nextToken()
-	expression.Right = p.parseExpression(PREFIX)
+	precedence := PREFIX
+	if expression.OpType == token.MINUS {
+		// like Python, - binds looser than **, so -2 ** 2 is -(2 ** 2)
+		precedence = POW - 1
+	}
+
+	// a minus applied directly to an integer literal is a negative IntegerLiteral; -x stays a PrefixExpression.
+	// The sign is parsed with the digits so -9223372036854775808 does not overflow.
+	if expression.OpType == token.MINUS && p.curTokenIs(token.INT) && p.peekPrecedence() <= precedence {
+		tok := expression.Token // keep the position of the minus
+		tok.Type = token.INT
+		tok.Literal = expression.Operator + p.curToken.Literal
+		return p.integerLiteral(tok)
+	}
+
+	expression.Right = p.parseExpression(precedence)
 	return expression
 }
 
@@ -215,18 +622,193 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	expression := &ast.InfixExpression{
 		Token:    p.curToken,
 		Operator: p.curToken.Literal,
+		OpType:   p.curToken.Type,
 		Left:     left,
 	}
-	precedence := p.curPrecedence()
+	precedence := p.operandPrecedence()
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
+	// 1 < x < 10 would compare the boolean 1 < x with 10
+	if chained, ok := left.(*ast.InfixExpression); ok && relational[chained.OpType] && relational[expression.OpType] {
+		msg := fmt.Sprintf("comparisons cannot be chained: compare %s %s %s and %s %s %s separately",
+			chained.Left, chained.Operator, chained.Right, chained.Right, expression.Operator, expression.Right)
+		// keep the tree whole so the program can still be printed
+		p.addError(expression.Token, msg)
+	}
+	return expression
+}
+
+// parsePostfixExpression
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	expression := &ast.PostfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		OpType:   p.curToken.Type,
+		Left:     left,
+	}
+	// a--b lexes as a-- b and a !b as a! b; an operand right after a postfix operator on the same line is a typo,
+	// not a new statement
+	if p.peekTokenIsOperand() && !p.peekTokenOnNewLine() {
+		msg := fmt.Sprintf("unexpected %s after %s%s", p.peekToken.Literal, left, p.curToken.Literal)
+		p.addError(p.peekToken, msg)
+	}
+	return expression
+}
+
+// peekTokenIsOperand returns true if the peek token can only begin an expression, not continue one
+func (p *Parser) peekTokenIsOperand() bool {
+	_, prefix := p.prefixParseFns[p.peekToken.Type]
+	_, infix := p.infixParseFns[p.peekToken.Type]
+	_, postfix := p.postfixParseFns[p.peekToken.Type]
+	return prefix && !infix && !postfix
+}
+
+// parseAssignExpression parses <name> = <value>; only an identifier can be assigned to
+func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
+	name, ok := left.(*ast.Identifier)
+	if !ok {
+		msg := fmt.Sprintf("cannot assign to %s", left)
+		p.addError(p.curToken, msg)
+		return nil
+	}
+	expression := &ast.AssignExpression{Token: p.curToken, Name: name}
+	// assignment is right-associative, so a = b = 5 is a = (b = 5)
+	precedence := p.operandPrecedence()
+	p.nextToken()
+	expression.Value = p.parseExpression(precedence)
+	return expression
+}
+
+// parseMemberExpression
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	expression := &ast.MemberExpression{Token: p.curToken, Object: object}
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	expression.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	return expression
 }
 
-// nextToken method sets the parser's current token and peek token
+// parseSwitchExpression parses switch (<subject>) { case <value>: <statements> ... default: <statements> }
+func (p *Parser) parseSwitchExpression() ast.Expression {
+	expression := &ast.SwitchExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	p.parens++
+	expression.Subject = p.parseExpression(LOWEST)
+	p.parens--
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) {
+		switch p.curToken.Type {
+		case token.CASE:
+			clause := &ast.CaseClause{Token: p.curToken}
+			p.nextToken()
+			clause.Value = p.parseExpression(LOWEST)
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			clause.Body = p.parseCaseBody()
+			expression.Cases = append(expression.Cases, clause)
+		case token.DEFAULT:
+			if expression.Default != nil {
+				p.addError(p.curToken, "duplicate default in switch")
+			}
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			expression.Default = p.parseCaseBody()
+		case token.EOF:
+			p.addError(p.curToken, "expected }, got EOF instead")
+			return nil
+		default:
+			msg := fmt.Sprintf("expected case or default, got %s instead", p.curToken.Type)
+			p.addError(p.curToken, msg)
+			return nil
+		}
+	}
+	return expression
+}
+
+// parseCaseBody parses the statements following a case or default ':' up to the next case, default, or closing brace,
+// leaving that token current
+func (p *Parser) parseCaseBody() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken}
+	block.Statements = []ast.Statement{}
+	parens := p.parens
+	p.parens = 0
+	p.nextToken()
+	for !p.curTokenIs(token.CASE) && !p.curTokenIs(token.DEFAULT) && !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+	p.parens = parens
+	return block
+}
+
+// parseConditionalExpression parses <consequence> if <condition> else <alternative>; chains group to the right
+func (p *Parser) parseConditionalExpression(consequence ast.Expression) ast.Expression {
+	expression := &ast.IfExpression{
+		Token:       p.curToken,
+		Consequence: consequence,
+	}
+	p.nextToken()
+	expression.Condition = p.parseExpression(CONDITIONAL)
+	if !p.expectPeek(token.ELSE) {
+		return nil
+	}
+	p.nextToken()
+	// parse the alternative just below CONDITIONAL so a following 'if' nests inside it (right-associative)
+	expression.Alternative = p.parseExpression(CONDITIONAL - 1)
+	return expression
+}
+
+// nextToken method sets the parser's current token and peek tokens, reading from the lexer once the buffer is exhausted
 func (p *Parser) nextToken() {
+	if p.position == len(p.tokens) {
+		tok := p.l.NextToken()
+		// comments only reach the parser from a lexer that emits them, and have no meaning to it
+		for tok.Type == token.COMMENT {
+			tok = p.l.NextToken()
+		}
+		p.tokens = append(p.tokens, tok)
+	}
 	p.curToken = p.peekToken
-	p.peekToken = p.l.NextToken()
+	p.peekToken = p.peek2Token
+	p.peek2Token = p.tokens[p.position]
+	p.position++
+}
+
+// discardTokens drops the buffered tokens before curToken, keeping any read ahead after a reset;
+// ParseEach calls it between top-level statements, so a mark is only valid within the statement it was taken in
+func (p *Parser) discardTokens() {
+	n := copy(p.tokens, p.tokens[p.position-3:])
+	p.tokens = p.tokens[:n]
+	p.position = 3
+}
+
+// mark returns the parser's position in the token stream, so a speculative parse can be undone with reset
+func (p *Parser) mark() int {
+	return p.position
+}
+
+// reset rewinds the parser to a position returned by mark; errors recorded since the mark are not removed
+func (p *Parser) reset(mark int) {
+	p.position = mark
+	p.curToken = p.tokens[mark-3]
+	p.peekToken = p.tokens[mark-2]
+	p.peek2Token = p.tokens[mark-1]
 }
 
 // curTokenIs returns true if the parser's current token type is the provided token type
@@ -239,6 +821,17 @@ func (p *Parser) peekTokenIs(t token.Type) bool {
 	return p.peekToken.Type == t
 }
 
+// peekTokenOnNewLine returns true if the parser's peek token starts on a later line than the current token,
+// unless a line continuation joins the two lines or they are inside a parenthesized header
+func (p *Parser) peekTokenOnNewLine() bool {
+	return p.parens == 0 && p.peekToken.Line > p.curToken.Line && !p.peekToken.Continued
+}
+
+// peek2TokenIs returns true if the token after the parser's peek token is the provided token type
+func (p *Parser) peek2TokenIs(t token.Type) bool {
+	return p.peek2Token.Type == t
+}
+
 // expectPeek method advances the current and peek token and returns true if the parser's peek token type is the provided token type
 func (p *Parser) expectPeek(t token.Type) bool {
 	if p.peekTokenIs(t) {
@@ -249,36 +842,51 @@ func (p *Parser) expectPeek(t token.Type) bool {
 	return false
 }
 
-// Errors returns parser errors array
+// Errors returns parser error messages array
 func (p *Parser) Errors() []string {
+	errors := make([]string, len(p.errors))
+	for i, e := range p.errors {
+		errors[i] = e.Message
+	}
+	return errors
+}
+
+// ParseErrors returns parser errors array, including the position of each error
+func (p *Parser) ParseErrors() []ParseError {
 	return p.errors
 }
 
+// addError appends an error message positioned at the provided token to the parser errors array
+func (p *Parser) addError(tok token.Token, msg string) {
+	p.errors = append(p.errors, ParseError{Message: msg, Line: tok.Line, Column: tok.Column})
+}
+
 // peekError appends an error message to the parser errors array
 func (p *Parser) peekError(t token.Type) {
 	msg := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.addError(p.peekToken, msg)
 }
 
 func (p *Parser) peekPrecedence() int {
-	if p, ok := precedences[p.peekToken.Type]; ok {
-		return p
-	}
-
-	return LOWEST
+	return precedence(p.peekToken.Type)
 }
 
 func (p *Parser) curPrecedence() int {
-	if p, ok := precedences[p.curToken.Type]; ok {
-		return p
-	}
+	return precedence(p.curToken.Type)
+}
 
-	return LOWEST
+// operandPrecedence returns the precedence to parse the right operand of the current operator with; recursing with a
+// lower precedence lets the right operand absorb a following operator of the same precedence
+func (p *Parser) operandPrecedence() int {
+	if rightAssociative[p.curToken.Type] {
+		return p.curPrecedence() - 1
+	}
+	return p.curPrecedence()
 }
 
 func (p *Parser) noPrefixParseFnError(t token.Type) {
 	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	p.addError(p.curToken, msg)
 }
 
 // registerPrefix
@@ -290,3 +898,8 @@ func (p *Parser) registerPrefix(tokenType token.Type, fn prefixParseFn) {
 func (p *Parser) registerInfix(tokenType token.Type, fn infixParseFn) {
 	p.infixParseFns[tokenType] = fn
 }
+
+// registerPostfix
+func (p *Parser) registerPostfix(tokenType token.Type, fn postfixParseFn) {
+	p.postfixParseFns[tokenType] = fn
+}
@@ -2,10 +2,13 @@ package parser
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/esquivias/interpreter/ast"
 	"github.com/esquivias/interpreter/lexer"
+	"github.com/esquivias/interpreter/token"
 )
 
 func TestLetStatements(t *testing.T) {
@@ -25,7 +28,7 @@ func TestLetStatements(t *testing.T) {
 	}
 
 	if len(program.Statements) != 3 {
-		t.Fatalf("program.Statements does not contain 3 statements. got=%q",
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d",
 			len(program.Statements),
 		)
 	}
@@ -45,6 +48,95 @@ func TestLetStatements(t *testing.T) {
 		}
 	}
 }
+func TestLetWithoutInitializer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x;", "let x;"},
+		{"let x: int;", "let x: int;"},
+		{"let x", "let x;"},
+		{"let x\n", "let x;"},
+		{"let x: int\n", "let x: int;"},
+		{"while (y) { let x }", "while (y) { let x; }"},
+		{"for (let i; i < 10; i++) {}", "for (let i; (i < 10); (i++)) { }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	program, errors := Parse("let x;")
+	if len(errors) != 0 {
+		t.Fatalf("parser errors: %q", errors)
+	}
+	stmt := program.Statements[0].(*ast.LetStatement)
+	if stmt.Name.Value != "x" || stmt.Value != nil {
+		t.Errorf("stmt wrong. got name=%q, value=%v", stmt.Name.Value, stmt.Value)
+	}
+
+	program, errors = Parse("let x\nx = 5")
+	if len(errors) != 0 || len(program.Statements) != 2 {
+		t.Errorf("wrong program for a declaration ended by a newline. got=%q, errors=%q", program.String(), errors)
+	}
+
+	_, errors = Parse("let x 5;")
+	if len(errors) == 0 || errors[0] != "expected next token to be =, got INT instead" {
+		t.Errorf("wrong errors for a missing =. got=%q", errors)
+	}
+}
+func TestLetTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedType string
+		expected     string
+	}{
+		{"let x: int = 5;", "int", "let x: int = 5;"},
+		{"let c : char = 'a';", "char", "let c: char = 'a';"},
+		{"let y = 10;", "", "let y = 10;"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if tt.expectedType == "" {
+			if stmt.Type != nil {
+				t.Errorf("stmt.Type not nil. got=%q", stmt.Type.Value)
+			}
+		} else if stmt.Type == nil || stmt.Type.Value != tt.expectedType {
+			t.Errorf("stmt.Type not %q. got=%v", tt.expectedType, stmt.Type)
+		}
+		if stmt.String() != tt.expected {
+			t.Errorf("stmt.String() wrong. expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+
+	l := lexer.New("let x: 5 = 5;")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "expected next token to be IDENT, got INT instead" {
+		t.Errorf("wrong errors for a missing type name. got=%q", errors)
+	}
+}
 func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 	if s.TokenLiteral() != "let" {
 		t.Errorf("s.TokenLiteral not 'let'. got=%q", s.TokenLiteral())
@@ -83,7 +175,7 @@ func TestReturnStatements(t *testing.T) {
 		t.Fatalf("program.Statements does not contain 3 statements. got=%d",
 			len(program.Statements))
 	}
-	for _, stmt := range program.Statements {
+	for i, stmt := range program.Statements {
 		returnStmt, ok := stmt.(*ast.ReturnStatement)
 		if !ok {
 			t.Errorf("stmt not *ast.returnStatement. got=%T", stmt)
@@ -93,6 +185,130 @@ func TestReturnStatements(t *testing.T) {
 			t.Errorf("returnStmt.TokenLiteral not 'return', got %q",
 				returnStmt.TokenLiteral())
 		}
+		testIntegerLiteral(t, returnStmt.ReturnValue, []int64{5, 10, 993322}[i])
+	}
+
+	// a return without a value
+	bareTests := []struct {
+		input    string
+		expected string
+	}{
+		{"return;", "return;"},
+		{"return", "return;"},
+		{"while (x) { return }", "while (x) { return; }"},
+		{"return\nx", "return;\nx"},
+		{"return x\n", "return x;"},
+	}
+
+	for _, tt := range bareTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+func TestComments(t *testing.T) {
+	input := "let x = 1; // one\nx /* two */ + 2\n// three\n-x"
+	expected := "let x = 1;\n(x + 2)\n(-x)"
+
+	for _, l := range []*lexer.Lexer{lexer.New(input), lexer.NewWithComments(input)} {
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != expected {
+			t.Errorf("expected=%q, got=%q", expected, program.String())
+		}
+	}
+}
+func TestEmptyProgram(t *testing.T) {
+	tests := []string{"", " ", "\n\n", "\t\r\n"}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program == nil {
+			t.Fatalf("ParseProgram() returned nil for %q", input)
+		}
+		if program.Statements == nil || len(program.Statements) != 0 {
+			t.Errorf("program.Statements not empty for %q. got=%v", input, program.Statements)
+		}
+		if program.TokenLiteral() != "" {
+			t.Errorf("program.TokenLiteral() not empty for %q. got=%q", input, program.TokenLiteral())
+		}
+		if program.String() != "" {
+			t.Errorf("program.String() not empty for %q. got=%q", input, program.String())
+		}
+	}
+}
+func TestNewlineTerminatedStatements(t *testing.T) {
+	input := `
+	let x = 5
+	let y = x +
+		10
+	x
+	-y
+	x \
+	-y
+	return x * y
+	while (x) { x
+		y }
+	`
+	expected := []string{
+		"let x = 5;",
+		"let y = (x + 10);",
+		"x",
+		"(-y)",
+		"(x - y)",
+		"return (x * y);",
+		"while (x) { x y }",
+	}
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != len(expected) {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d: %q",
+			len(expected), len(program.Statements), program.String())
+	}
+
+	for i, stmt := range program.Statements {
+		if stmt.String() != expected[i] {
+			t.Errorf("Statements[%d] wrong. expected=%q, got=%q", i, expected[i], stmt.String())
+		}
+	}
+
+	// newlines inside a parenthesized header do not end anything, but they end statements in a block within one
+	headerTests := []struct {
+		input    string
+		expected string
+	}{
+		{"while (a\n< b) {}", "while ((a < b)) { }"},
+		{"while (\na\n) { x\n-y }", "while (a) { x (-y) }"},
+		{"do {} while (a\n> b)\n-c", "do { } while ((a > b))\n(-c)"},
+		{"for (let i = 0\n; i\n< n\n; i++) {}", "for (let i = 0; (i < n); (i++)) { }"},
+		{"switch (a\n+ b) {}", "switch ((a + b)) { }"},
+		{"while (switch (a) { case 1: x\n-y }) {}", "while (switch (a) { case 1: { x (-y) } }) { }"},
+	}
+
+	for _, tt := range headerTests {
+		program, errors := Parse(tt.input)
+		if len(errors) != 0 {
+			t.Errorf("parser errors for %q: %q", tt.input, errors)
+			continue
+		}
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("wrong program for %q. expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
 	}
 }
 func TestIdentifierExpression(t *testing.T) {
@@ -154,6 +370,123 @@ func TestIntegerLiteralExpression(t *testing.T) {
 			literal.TokenLiteral())
 	}
 }
+func TestCharLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue rune
+		expectedText  string
+	}{
+		{`'a';`, 'a', `'a'`},
+		{`'\n';`, '\n', `'\n'`},
+		{`'é';`, 'é', `'é'`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.CharLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.CharLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value != tt.expectedValue {
+			t.Errorf("literal.Value not %q. got=%q", tt.expectedValue, literal.Value)
+		}
+		if literal.String() != tt.expectedText {
+			t.Errorf("literal.String() not %s. got=%s", tt.expectedText, literal.String())
+		}
+	}
+}
+func TestNullLiteralExpression(t *testing.T) {
+	input := "null;"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program has not enough statements. got=%d",
+			len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.NullLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.NullLiteral. got=%T", stmt.Expression)
+	}
+	if literal.TokenLiteral() != "null" {
+		t.Errorf("literal.TokenLiteral not %s. got=%s", "null",
+			literal.TokenLiteral())
+	}
+}
+func TestIntegerLiteralSuffixes(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue int64
+	}{
+		{"1k;", 1000},
+		{"2M;", 2000000},
+		{"3G;", 3000000000},
+		{"4T;", 4000000000000},
+		{"5;", 5},
+	}
+
+	for _, tt := range tests {
+		l := lexer.NewWithSuffixes(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("exp not *ast.IntegerLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value != tt.expectedValue {
+			t.Errorf("literal.Value not %d. got=%d", tt.expectedValue, literal.Value)
+		}
+	}
+
+	errorTests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"1kb;", `unknown integer suffix "kb" in "1kb"`},
+		{"1x;", `unknown integer suffix "x" in "1x"`},
+		{"9999999T;", `could not parse "9999999T" as integer`},
+		{"-9999999T;", `could not parse "-9999999T" as integer`},
+		{"-9223372036854775809;", `could not parse "-9223372036854775809" as integer`},
+	}
+
+	for _, tt := range errorTests {
+		l := lexer.NewWithSuffixes(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 || errors[0] != tt.expectedError {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expectedError, errors)
+		}
+	}
+}
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input        string
@@ -161,7 +494,7 @@ func TestParsingPrefixExpressions(t *testing.T) {
 		integerValue int64
 	}{
 		{"!5;", "!", 5},
-		{"-15;", "-", 15},
+		{"!-15;", "!", -15},
 	}
 
 	for _, tt := range prefixTests {
@@ -215,6 +548,63 @@ func testIntegerLiteral(t *testing.T, il ast.Expression, value int64) bool {
 	return true
 }
 
+func TestNegativeIntegerLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"-5;", "-5"},
+		{"-5 + 3;", "(-5 + 3)"},
+		{"3 - -5;", "(3 - -5)"},
+		{"-a;", "(-a)"},
+		{"-a++;", "(-(a++))"},
+		{"- -5;", "(- -5)"},
+		{"- -a;", "(-(-a))"},
+		{"-9223372036854775808;", "-9223372036854775808"},
+		{"-5 ** 2;", "(-(5 ** 2))"},
+		{"-5++;", "(-(5++))"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	l := lexer.New("-15;")
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+	if !testIntegerLiteral(t, stmt.Expression, -15) {
+		return
+	}
+	if stmt.Expression.(*ast.IntegerLiteral).Token.Type != token.INT {
+		t.Errorf("literal token type not INT. got=%q", stmt.Expression.(*ast.IntegerLiteral).Token.Type)
+	}
+
+	l = lexer.NewWithSuffixes("-2k;")
+	p = New(l)
+	program = p.ParseProgram()
+	checkParserErrors(t, p)
+
+	literal, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IntegerLiteral)
+	if !ok || literal.Value != -2000 {
+		t.Errorf("-2k not folded into -2000. got=%s", program.String())
+	}
+}
+
 func TestParsingInfixExpressions(t *testing.T) {
 	infixTests := []struct {
 		input      string
@@ -230,6 +620,8 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 < 5;", 5, "<", 5},
 		{"5 == 5;", 5, "==", 5},
 		{"5 != 5;", 5, "!=", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 ?? 5;", 5, "??", 5},
 	}
 
 	for _, tt := range infixTests {
@@ -268,6 +660,72 @@ func TestParsingInfixExpressions(t *testing.T) {
 		}
 	}
 }
+func TestParsingPostfixExpressions(t *testing.T) {
+	postfixTests := []struct {
+		input    string
+		operator string
+		opType   token.Type
+	}{
+		{"i++;", "++", token.INC},
+		{"i--;", "--", token.DEC},
+		{"i!;", "!", token.BANG},
+	}
+
+	for _, tt := range postfixTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+				1, len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.PostfixExpression)
+		if !ok {
+			t.Fatalf("stmt is not ast.PostfixExpression. got=%T", stmt.Expression)
+		}
+		if exp.Operator != tt.operator || exp.OpType != tt.opType {
+			t.Fatalf("exp.Operator is not '%s'. got=%s",
+				tt.operator, exp.Operator)
+		}
+		if exp.Left.String() != "i" {
+			t.Fatalf("exp.Left is not 'i'. got=%s", exp.Left.String())
+		}
+	}
+
+	// -- and ++ are single tokens, so they are never read as two signs, and ! after an operand is factorial
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"a--b", "unexpected b after a--"},
+		{"a++ 1", "unexpected 1 after a++"},
+		{"a !b", "unexpected b after a!"},
+		{"--5", "no prefix parse function for -- found"},
+	}
+
+	for _, tt := range errorTests {
+		_, errors := Parse(tt.input)
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+
+	// an operand on the next line begins a new statement
+	for _, input := range []string{"a--\nb", "a-- - b", "a++;b", "a!\nb"} {
+		if _, errors := Parse(input); len(errors) != 0 {
+			t.Errorf("parser errors for %q: %q", input, errors)
+		}
+	}
+}
 func TestOperatorPrecedenceParsing(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -307,21 +765,984 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		},
 		{
 			"3 + 4; -5 * 5",
-			"(3 + 4)((-5) * 5)",
+			"(3 + 4)\n(-5 * 5)",
 		},
 		{
 			"5 > 4 == 3 < 4",
 			"((5 > 4) == (3 < 4))",
 		},
 		{
-			"5 < 4 != 3 > 4",
-			"((5 < 4) != (3 > 4))",
+			"5! + 1",
+			"((5!) + 1)",
 		},
 		{
-			"3 + 4 * 5 == 3 * 1 + 4 * 5",
-			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
+			"-5!",
+			"(-(5!))",
 		},
-	}
+		{
+			"!n!",
+			"(!(n!))",
+		},
+		{
+			"2 ** 3! * 2",
+			"((2 ** (3!)) * 2)",
+		},
+		{
+			"n!!",
+			"((n!)!)",
+		},
+		{
+			"a! != b",
+			"((a!) != b)",
+		},
+		{
+			"5 < 4 == 3 > 4",
+			"((5 < 4) == (3 > 4))",
+		},
+		{
+			"5 < 4 != 3 > 4",
+			"((5 < 4) != (3 > 4))",
+		},
+		{
+			"3 + 4 * 5 == 3 * 1 + 4 * 5",
+			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
+		},
+		{
+			"-a++",
+			"(-(a++))",
+		},
+		{
+			"a + b-- * c",
+			"(a + ((b--) * c))",
+		},
+		{
+			"2 ** 3 ** 2",
+			"(2 ** (3 ** 2))",
+		},
+		{
+			"a * b ** c * d",
+			"((a * (b ** c)) * d)",
+		},
+		{
+			"a ** b++",
+			"(a ** (b++))",
+		},
+		{
+			"-2 ** 2",
+			"(-(2 ** 2))",
+		},
+		{
+			"-a ** b ** c",
+			"(-(a ** (b ** c)))",
+		},
+		{
+			"-a ** b * c",
+			"((-(a ** b)) * c)",
+		},
+		{
+			"2 ** -a",
+			"(2 ** (-a))",
+		},
+		{
+			"!a ** b",
+			"((!a) ** b)",
+		},
+		{
+			"a ?? b + c",
+			"(a ?? (b + c))",
+		},
+		{
+			"a == b ?? c < d",
+			"((a == b) ?? (c < d))",
+		},
+		{
+			"a ?? b if c else d",
+			"((a ?? b) if c else d)",
+		},
+		{
+			"null == null",
+			"(null == null)",
+		},
+		{
+			"a.b.c",
+			"((a.b).c)",
+		},
+		{
+			"-a.b ** c.d",
+			"(-((a.b) ** (c.d)))",
+		},
+		{
+			"a.b++ * c",
+			"(((a.b)++) * c)",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+func TestOperatorTypes(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOpType token.Type
+	}{
+		{"!5;", token.BANG},
+		{"-a;", token.MINUS},
+		{"5 - 5;", token.MINUS},
+		{"5 < 5;", token.LT},
+		{"5 == 5;", token.EQ},
+		{"5 != 5;", token.NEQ},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0])
+		}
+
+		var opType token.Type
+		switch exp := stmt.Expression.(type) {
+		case *ast.PrefixExpression:
+			opType = exp.OpType
+		case *ast.InfixExpression:
+			opType = exp.OpType
+		default:
+			t.Fatalf("exp is not a prefix or infix expression. got=%T", stmt.Expression)
+		}
+
+		if opType != tt.expectedOpType {
+			t.Errorf("OpType wrong for %q. expected=%q, got=%q",
+				tt.input, tt.expectedOpType, opType)
+		}
+	}
+}
+func TestWhileStatement(t *testing.T) {
+	input := `while (x < y) { x; y }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.WhileStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if stmt.Condition.String() != "(x < y)" {
+		t.Errorf("stmt.Condition wrong. got=%q", stmt.Condition.String())
+	}
+
+	if len(stmt.Body.Statements) != 2 {
+		t.Fatalf("body is not 2 statements. got=%d\n", len(stmt.Body.Statements))
+	}
+
+	for i, expected := range []string{"x", "y"} {
+		body, ok := stmt.Body.Statements[i].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("Statements[%d] is not ast.ExpressionStatement. got=%T",
+				i, stmt.Body.Statements[i])
+		}
+		if body.String() != expected {
+			t.Errorf("Statements[%d] wrong. expected=%q, got=%q", i, expected, body.String())
+		}
+	}
+}
+func TestDoWhileStatement(t *testing.T) {
+	input := `do { x; y } while (x < y); z`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			2, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.DoWhileStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.DoWhileStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if stmt.Body.String() != "{ x y }" {
+		t.Errorf("stmt.Body wrong. got=%q", stmt.Body.String())
+	}
+	if stmt.Condition.String() != "(x < y)" {
+		t.Errorf("stmt.Condition wrong. got=%q", stmt.Condition.String())
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"do x while (y)", "expected next token to be {, got IDENT instead"},
+		{"do { x } (y)", "expected next token to be WHILE, got ( instead"},
+		{"do { x } while y", "expected next token to be (, got IDENT instead"},
+	}
+
+	for _, tt := range errorTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+func TestBreakContinueStatements(t *testing.T) {
+	input := `
+	while (x) { break; continue }
+	for (;;) {
+		continue
+		break
+	}
+	`
+	expected := []string{"break;", "continue;", "continue;", "break;"}
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	var statements []ast.Statement
+	statements = append(statements, program.Statements[0].(*ast.WhileStatement).Body.Statements...)
+	statements = append(statements, program.Statements[1].(*ast.ForStatement).Body.Statements...)
+
+	if len(statements) != len(expected) {
+		t.Fatalf("loop bodies do not contain %d statements. got=%d", len(expected), len(statements))
+	}
+
+	for i, stmt := range statements {
+		switch stmt.(type) {
+		case *ast.BreakStatement, *ast.ContinueStatement:
+		default:
+			t.Fatalf("statements[%d] is not a break or continue. got=%T", i, stmt)
+		}
+		if stmt.String() != expected[i] {
+			t.Errorf("statements[%d] wrong. expected=%q, got=%q", i, expected[i], stmt.String())
+		}
+	}
+}
+func TestStatementStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"while (a) { while (b) { c } d }", "while (a) { while (b) { c } d }"},
+		{"while (a) { b } c", "while (a) { b }\nc"},
+		{"do { do { a } while (b) } while (c)", "do { do { a } while (b) } while (c)"},
+		{"for (;;) { try { a } catch (e) { for (i; i; i) {} } }",
+			"for (; ; ) { try { a } catch (e) { for (i; i; i) { } } }"},
+		{"try { let x = 1; x } catch (e) { return e; }", "try { let x = 1; x } catch (e) { return e; }"},
+	}
+
+	// top-level statements are separated, so two statements never print as one
+	two, _ := Parse("a; b")
+	one, _ := Parse("ab")
+	if two.String() == one.String() {
+		t.Errorf("a; b and ab print the same. got=%q", two.String())
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+func TestForStatement(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedInit      string
+		expectedCondition string
+		expectedPost      string
+		expectedBody      string
+	}{
+		{"for (let i = 0; i < 10; i + 1) { i }", "let i = 0;", "(i < 10)", "(i + 1)", "{ i }"},
+		{"for (i; i < 10; i) { i; }", "i", "(i < 10)", "i", "{ i }"},
+		{"for (; i < 10;) { i }", "", "(i < 10)", "", "{ i }"},
+		{"for (;;) {}", "", "", "", "{ }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+				1, len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ForStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ForStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if init := nodeString(stmt.Init); init != tt.expectedInit {
+			t.Errorf("stmt.Init wrong for %q. expected=%q, got=%q", tt.input, tt.expectedInit, init)
+		}
+		if condition := nodeString(stmt.Condition); condition != tt.expectedCondition {
+			t.Errorf("stmt.Condition wrong for %q. expected=%q, got=%q", tt.input, tt.expectedCondition, condition)
+		}
+		if post := nodeString(stmt.Post); post != tt.expectedPost {
+			t.Errorf("stmt.Post wrong for %q. expected=%q, got=%q", tt.input, tt.expectedPost, post)
+		}
+		if body := stmt.Body.String(); body != tt.expectedBody {
+			t.Errorf("stmt.Body wrong for %q. expected=%q, got=%q", tt.input, tt.expectedBody, body)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (return 5; x; y) {}", "unexpected return in for initializer"},
+		{"for (break;;) {}", "unexpected break in for initializer"},
+		{"for (while (x) {};;) {}", "unexpected while in for initializer"},
+	}
+
+	for _, tt := range errorTests {
+		_, errors := Parse(tt.input)
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+func nodeString(node ast.Node) string {
+	if node == nil {
+		return ""
+	}
+	return node.String()
+}
+func TestSwitchExpression(t *testing.T) {
+	input := `
+	switch (x + 1) {
+	case 1: a; b
+	case y if z else 2:
+	default:
+		let c = 3
+		c
+	}
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.SwitchExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.SwitchExpression. got=%T", stmt.Expression)
+	}
+
+	if exp.Subject.String() != "(x + 1)" {
+		t.Errorf("exp.Subject wrong. got=%q", exp.Subject.String())
+	}
+
+	cases := []struct {
+		value string
+		body  string
+	}{
+		{"1", "{ a b }"},
+		{"(y if z else 2)", "{ }"},
+	}
+
+	if len(exp.Cases) != len(cases) {
+		t.Fatalf("exp.Cases does not contain %d cases. got=%d", len(cases), len(exp.Cases))
+	}
+	for i, tt := range cases {
+		if exp.Cases[i].Value.String() != tt.value {
+			t.Errorf("Cases[%d].Value wrong. expected=%q, got=%q", i, tt.value, exp.Cases[i].Value.String())
+		}
+		if exp.Cases[i].Body.String() != tt.body {
+			t.Errorf("Cases[%d].Body wrong. expected=%q, got=%q", i, tt.body, exp.Cases[i].Body.String())
+		}
+	}
+
+	if exp.Default == nil || exp.Default.String() != "{ let c = 3; c }" {
+		t.Errorf("exp.Default wrong. got=%v", exp.Default)
+	}
+
+	stringTests := []struct {
+		input    string
+		expected string
+	}{
+		{"switch (x) {}", "switch (x) { }"},
+		{"let y = switch (x) { default: 1 }", "let y = switch (x) { default: { 1 } };"},
+		{"switch (x) { case 1: 2 } + 3", "(switch (x) { case 1: { 2 } } + 3)"},
+	}
+
+	for _, tt := range stringTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"switch x {}", "expected next token to be (, got IDENT instead"},
+		{"switch (x) { 1 }", "expected case or default, got INT instead"},
+		{"switch (x) { case 1 2 }", "expected next token to be :, got INT instead"},
+		{"switch (x) { default: 1 default: 2 }", "duplicate default in switch"},
+		{"switch (x) { case 1: 2", "expected }, got EOF instead"},
+	}
+
+	for _, tt := range errorTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+func TestConditionalExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"let x = a if b else c;",
+			"let x = (a if b else c);",
+		},
+		{
+			"let x = a + 1 if b < c else d * 2;",
+			"let x = ((a + 1) if (b < c) else (d * 2));",
+		},
+		{
+			"let x = a if b == c else -d;",
+			"let x = (a if (b == c) else (-d));",
+		},
+		{
+			"a if b else c if d else e",
+			"(a if b else (c if d else e))",
+		},
+		{
+			"a if b else c + d",
+			"(a if b else (c + d))",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	l := lexer.New("let x = a if b;")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "expected next token to be ELSE, got ; instead" {
+		t.Errorf("wrong errors for a missing else. got=%q", errors)
+	}
+}
+func TestTryStatement(t *testing.T) {
+	input := `try { x; y } catch (e) { e }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.TryStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.TryStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if len(stmt.Body.Statements) != 2 {
+		t.Fatalf("body is not 2 statements. got=%d\n", len(stmt.Body.Statements))
+	}
+	if stmt.Body.String() != "{ x y }" {
+		t.Errorf("stmt.Body wrong. got=%q", stmt.Body.String())
+	}
+	if stmt.Parameter.Value != "e" {
+		t.Errorf("stmt.Parameter wrong. got=%q", stmt.Parameter.Value)
+	}
+	if len(stmt.Catch.Statements) != 1 || stmt.Catch.String() != "{ e }" {
+		t.Errorf("stmt.Catch wrong. got=%q", stmt.Catch.String())
+	}
+
+	errorTests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"try { x }", "expected next token to be CATCH, got EOF instead"},
+		{"try { x } catch { e }", "expected next token to be (, got { instead"},
+		{"try { x } catch () { e }", "expected next token to be IDENT, got ) instead"},
+	}
+
+	for _, tt := range errorTests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expectedError {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expectedError, errors)
+		}
+	}
+}
+func TestPeek2Token(t *testing.T) {
+	l := lexer.New("let x = 5;")
+	p := New(l)
+
+	tests := []struct {
+		cur, peek, peek2 token.Type
+	}{
+		{token.LET, token.IDENT, token.ASSIGN},
+		{token.IDENT, token.ASSIGN, token.INT},
+		{token.ASSIGN, token.INT, token.SEMICOLON},
+		{token.INT, token.SEMICOLON, token.EOF},
+		{token.SEMICOLON, token.EOF, token.EOF},
+	}
+
+	for i, tt := range tests {
+		if !p.curTokenIs(tt.cur) || !p.peekTokenIs(tt.peek) || !p.peek2TokenIs(tt.peek2) {
+			t.Fatalf("tests[%d] - tokens wrong. expected=%s %s %s, got=%s %s %s", i,
+				tt.cur, tt.peek, tt.peek2, p.curToken.Type, p.peekToken.Type, p.peek2Token.Type)
+		}
+		p.nextToken()
+	}
+}
+func TestMarkReset(t *testing.T) {
+	l := lexer.New("a + b * c; d;")
+	p := New(l)
+
+	mark := p.mark()
+	first := p.parseExpression(LOWEST)
+	if first.String() != "(a + (b * c))" {
+		t.Fatalf("first parse wrong. got=%q", first.String())
+	}
+	if !p.peekTokenIs(token.SEMICOLON) {
+		t.Fatalf("peekToken is not ;. got=%s", p.peekToken.Type)
+	}
+
+	p.reset(mark)
+	if !p.curTokenIs(token.IDENT) || p.curToken.Literal != "a" || !p.peekTokenIs(token.PLUS) || !p.peek2TokenIs(token.IDENT) {
+		t.Fatalf("tokens not rewound. got=%s %s %s", p.curToken.Type, p.peekToken.Type, p.peek2Token.Type)
+	}
+
+	// parse again from the mark, this time stopping before the '*'
+	second := p.parseExpression(SUM)
+	if second.String() != "a" {
+		t.Fatalf("second parse wrong. got=%q", second.String())
+	}
+
+	p.reset(mark)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	if program.String() != "(a + (b * c))\nd" {
+		t.Errorf("program wrong after reset. got=%q", program.String())
+	}
+}
+func TestMemberExpression(t *testing.T) {
+	input := "obj.field;"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.MemberExpression. got=%T", stmt.Expression)
+	}
+	if exp.Object.String() != "obj" {
+		t.Errorf("exp.Object is not 'obj'. got=%s", exp.Object.String())
+	}
+	if exp.Property.Value != "field" {
+		t.Errorf("exp.Property is not 'field'. got=%s", exp.Property.Value)
+	}
+
+	l = lexer.New("obj.5;")
+	p = New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "expected next token to be IDENT, got INT instead" {
+		t.Errorf("wrong errors for a non-identifier property. got=%q", errors)
+	}
+}
+func TestParseEach(t *testing.T) {
+	input := strings.Repeat("let x = 1 + 2;\nwhile (x) { x-- }\nlet = 3;\n", 100)
+
+	expected := New(lexer.New(input)).ParseProgram()
+
+	p := New(lexer.New(input))
+	var statements []ast.Statement
+	p.ParseEach(func(stmt ast.Statement) {
+		statements = append(statements, stmt)
+		// only the tokens of the current statement are kept, not those of every statement before it
+		if len(p.tokens) > 16 {
+			t.Fatalf("token buffer not discarded. got=%d tokens", len(p.tokens))
+		}
+	})
+
+	if !ast.Equal(&ast.Program{Statements: statements}, expected) {
+		t.Errorf("statements wrong. expected=%q, got=%q", expected.String(), (&ast.Program{Statements: statements}).String())
+	}
+
+	errors := p.Errors()
+	if len(errors) != 100 || errors[0] != "expected next token to be IDENT, got = instead" {
+		t.Errorf("wrong errors. got %d: %q", len(errors), errors[0])
+	}
+}
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input          string
+		expected       string
+		expectedErrors []string
+	}{
+		{"let x = 5; x * 2;", "let x = 5;\n(x * 2)", []string{}},
+		{"let = 5; y", "y", []string{"expected next token to be IDENT, got = instead"}},
+	}
+
+	for _, tt := range tests {
+		program, errors := Parse(tt.input)
+
+		if !reflect.DeepEqual(errors, tt.expectedErrors) {
+			t.Errorf("errors wrong for %q. expected=%q, got=%q", tt.input, tt.expectedErrors, errors)
+		}
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("program wrong for %q. expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+func TestEqualTrees(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"let x = a + b * c;", "let x =\n\ta + b *\n\tc", true},
+		{"while (x) { x-- }", "while(x){x--;}", true},
+		{"switch (x) { case 1: a default: b }", "switch (x) {\ncase 1:\n\ta;\ndefault:\n\tb;\n}", true},
+		{"try { a } catch (e) { e }", "try { a } catch (f) { f }", false},
+		{"a + b * c", "a * b + c", false},
+		{"-5", "- 5", true},
+		{"- -5", "-5", false},
+		{"let x: int = 1;", "let x = 1;", false},
+		{"for (;;) { do { break } while (a) }", "for (; ;) {do {break;} while (a);}", true},
+	}
+
+	for _, tt := range tests {
+		a, errors := Parse(tt.a)
+		if len(errors) != 0 {
+			t.Fatalf("parser errors for %q: %q", tt.a, errors)
+		}
+		b, errors := Parse(tt.b)
+		if len(errors) != 0 {
+			t.Fatalf("parser errors for %q: %q", tt.b, errors)
+		}
+
+		if actual := ast.Equal(a, b); actual != tt.expected {
+			t.Errorf("ast.Equal(%q, %q) wrong. expected=%t, got=%t", tt.a, tt.b, tt.expected, actual)
+		}
+	}
+}
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		input          string
+		expected       string
+		expectedErrors []string
+	}{
+		{"1 + 2 * 3", "(1 + (2 * 3))", []string{}},
+		{"a ?? b;", "(a ?? b)", []string{}},
+		{"-x\n", "(-x)", []string{}},
+		{"a b", "a", []string{"expected end of expression, got IDENT instead"}},
+		{"a; b", "a", []string{"expected end of expression, got IDENT instead"}},
+		{"let x = 5;", "", []string{"no prefix parse function for LET found"}},
+		{"", "", []string{"no prefix parse function for EOF found"}},
+	}
+
+	for _, tt := range tests {
+		expression, errors := ParseExpression(tt.input)
+
+		if !reflect.DeepEqual(errors, tt.expectedErrors) {
+			t.Errorf("errors wrong for %q. expected=%q, got=%q", tt.input, tt.expectedErrors, errors)
+		}
+		if actual := nodeString(expression); actual != tt.expected {
+			t.Errorf("expression wrong for %q. expected=%q, got=%q", tt.input, tt.expected, actual)
+		}
+	}
+}
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = b = 5;", "let a = (b = 5);"},
+		{"let a = b = c = 5;", "let a = (b = (c = 5));"},
+		{"a = b = 5;", "(a = (b = 5))"},
+		{"a = b + 1 if c else d ?? e;", "(a = ((b + 1) if c else (d ?? e)))"},
+		{"for (i = 0; i < 10; i = i + 1) {}", "for ((i = 0); (i < 10); (i = (i + 1))) { }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	l := lexer.New("let a = 1 + b = 5;")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "cannot assign to (1 + b)" {
+		t.Errorf("wrong errors for assigning to an expression. got=%q", errors)
+	}
+}
+func TestUnterminatedBlock(t *testing.T) {
+	tests := []string{
+		"while (x) { x;",
+		"while (x) {",
+		"for (;;) { while (y) { y; }",
+		"try { a; } catch (e) { b;",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 || errors[0] != "expected }, got EOF instead" {
+			t.Errorf("wrong errors for %q. got=%q", input, errors)
+		}
+	}
+}
+func TestErrorRecovery(t *testing.T) {
+	input := `
+	let = 5;
+	let y = 10;
+	`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("parser does not have 1 error. got=%d: %q", len(errors), errors)
+	}
+	if errors[0] != "expected next token to be IDENT, got = instead" {
+		t.Errorf("wrong error message. got=%q", errors[0])
+	}
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+			len(program.Statements))
+	}
+	testLetStatement(t, program.Statements[0], "y")
+
+	// errors inside a block recover at the next statement or the closing brace
+	blockTests := []struct {
+		input          string
+		expectedErrors []string
+		expectedBlock  int // statements in the while body
+		expectedLast   string
+	}{
+		{"while (x) { 1 + }\nlet z = 2;",
+			[]string{"no prefix parse function for } found"}, 1, "let z = 2;"},
+		{"while (x) { let = 5; let y = 1; }",
+			[]string{"expected next token to be IDENT, got = instead"}, 1, "while (x) { let y = 1; }"},
+		{"while (x) { while (y) { let = 1; } z; }",
+			[]string{"expected next token to be IDENT, got = instead"}, 2, "while (x) { while (y) { } z }"},
+	}
+
+	for _, tt := range blockTests {
+		program, errors := Parse(tt.input)
+		if len(errors) != len(tt.expectedErrors) {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expectedErrors, errors)
+			continue
+		}
+		for i, e := range tt.expectedErrors {
+			if errors[i] != e {
+				t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, e, errors[i])
+			}
+		}
+		while, ok := program.Statements[0].(*ast.WhileStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.WhileStatement. got=%T", program.Statements[0])
+		}
+		if len(while.Body.Statements) != tt.expectedBlock {
+			t.Errorf("wrong body for %q. expected %d statements, got=%d", tt.input, tt.expectedBlock,
+				len(while.Body.Statements))
+		}
+		last := program.Statements[len(program.Statements)-1]
+		if last.String() != tt.expectedLast {
+			t.Errorf("wrong last statement for %q. expected=%q, got=%q", tt.input, tt.expectedLast, last.String())
+		}
+	}
+}
+func TestParseErrorPositions(t *testing.T) {
+	input := `let x = 5;
+let = 10;
+  let y 7;`
+	l := lexer.New(input)
+	p := New(l)
+	p.ParseProgram()
+
+	tests := []struct {
+		expectedMessage string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"expected next token to be IDENT, got = instead", 2, 5},
+		{"expected next token to be =, got INT instead", 3, 9},
+	}
+
+	errors := p.ParseErrors()
+	if len(errors) != len(tests) {
+		t.Fatalf("parser does not have %d errors. got=%d: %v", len(tests), len(errors), errors)
+	}
+
+	for i, tt := range tests {
+		if errors[i].Message != tt.expectedMessage {
+			t.Errorf("errors[%d] - message wrong. expected=%q, got=%q",
+				i, tt.expectedMessage, errors[i].Message)
+		}
+		if errors[i].Line != tt.expectedLine || errors[i].Column != tt.expectedColumn {
+			t.Errorf("errors[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, errors[i].Line, errors[i].Column)
+		}
+		if p.Errors()[i] != tt.expectedMessage {
+			t.Errorf("Errors()[%d] wrong. expected=%q, got=%q",
+				i, tt.expectedMessage, p.Errors()[i])
+		}
+	}
+}
+func TestChainedComparisons(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 < x < 10", "comparisons cannot be chained: compare 1 < x and x < 10 separately"},
+		{"a > b > c", "comparisons cannot be chained: compare a > b and b > c separately"},
+		{"a < b + 1 > -c", "comparisons cannot be chained: compare a < (b + 1) and (b + 1) > (-c) separately"},
+		{"let ok = 0 < x < n;", "comparisons cannot be chained: compare 0 < x and x < n separately"},
+	}
+
+	for _, tt := range tests {
+		_, errors := Parse(tt.input)
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+
+	// the program is still built, grouping the comparisons from the left
+	program, errors := Parse("1 < 2 < 3 < 4")
+	if len(errors) != 2 {
+		t.Errorf("wrong errors for a chain of three comparisons. got=%q", errors)
+	}
+	if actual := program.String(); actual != "(((1 < 2) < 3) < 4)" {
+		t.Errorf("wrong program for a chain of three comparisons. got=%q", actual)
+	}
+
+	// other operators, and comparisons separated by an operator of lower precedence, are not chains
+	for _, input := range []string{"a < b == c > d", "a == b == c", "a < b ?? c < d", "a < b if c > d else e < f", "a = b < c"} {
+		if _, errors := Parse(input); len(errors) != 0 {
+			t.Errorf("parser errors for %q: %q", input, errors)
+		}
+	}
+}
+func TestOperatorAssociativity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// left-associative operators group left-to-right
+		{"a == b == c", "((a == b) == c)"},
+		{"a != b != c", "((a != b) != c)"},
+		{"a + b + c", "((a + b) + c)"},
+		{"a - b - c", "((a - b) - c)"},
+		{"a * b * c", "((a * b) * c)"},
+		{"a / b / c", "((a / b) / c)"},
+		// right-associative operators group right-to-left
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"a ** b ** c ** d", "(a ** (b ** (c ** d)))"},
+		{"a ?? b ?? c", "(a ?? (b ?? c))"},
+		// mixing does not leak associativity across precedence levels
+		{"a - b ** c - d", "((a - (b ** c)) - d)"},
+		{"a ** b - c ** d", "((a ** b) - (c ** d))"},
+	}
 
 	for _, tt := range tests {
 		l := lexer.New(tt.input)
@@ -346,3 +1767,32 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	}
 	t.FailNow()
 }
+
+// arithmeticInput is an operator-heavy program of about 100KB
+var arithmeticInput = strings.Repeat("let x = a + b * c - d / e ** f ** g;\nx = -a * b + c ?? d - e++ * f! / g.h;\n", 1000)
+
+func BenchmarkParseArithmetic(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(arithmeticInput)))
+	for i := 0; i < b.N; i++ {
+		p := New(lexer.New(arithmeticInput))
+		p.ParseProgram()
+		if len(p.errors) != 0 {
+			b.Fatalf("parser errors: %v", p.Errors())
+		}
+	}
+}
+
+func BenchmarkPrecedence(b *testing.B) {
+	types := []token.Type{token.IDENT, token.PLUS, token.INT, token.ASTERISK, token.SEMICOLON,
+		token.LET, token.POW, token.DOT, token.ASSIGN, token.EOF}
+	total := 0
+	for i := 0; i < b.N; i++ {
+		for _, t := range types {
+			total += precedence(t)
+		}
+	}
+	if total < 0 {
+		b.Fatal("negative precedence")
+	}
+}
@@ -4,28 +4,117 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"strings"
 
 	"github.com/esquivias/interpreter/lexer"
+	"github.com/esquivias/interpreter/parser"
 	"github.com/esquivias/interpreter/token"
 )
 
 const PROMPT = ">> "
 
+// Meta-commands switch how subsequent input is handled
+const (
+	// EVAL parses (and eventually evaluates) input; the default mode
+	EVAL = ":eval"
+	// TOKENS prints the tokens of the input, one per line
+	TOKENS = ":tokens"
+	// AST prints the parsed program, fully parenthesized
+	AST = ":ast"
+)
+
+var modes = map[string]bool{
+	EVAL:   true,
+	TOKENS: true,
+	AST:    true,
+}
+
+// Start reads lines from in and writes the result of each to out until in is exhausted
 func Start(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
+	mode := EVAL
 
 	for {
-		fmt.Printf(PROMPT)
+		fmt.Fprint(out, PROMPT)
 		scanned := scanner.Scan()
 		if !scanned {
 			return
 		}
 
 		line := scanner.Text()
-		l := lexer.New(line)
+		if command := strings.TrimSpace(line); modes[command] {
+			mode = command
+			continue
+		}
 
-		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
-			fmt.Printf("%+v\n", tok)
+		switch mode {
+		case TOKENS:
+			printTokens(out, line)
+		case AST:
+			printAST(out, line)
+		default:
+			eval(out, line)
 		}
 	}
 }
+
+// printTokens writes every token in line to out
+func printTokens(out io.Writer, line string) {
+	l := lexer.New(line)
+
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		fmt.Fprintf(out, "%+v\n", tok)
+	}
+}
+
+// eval parses line and writes the parser errors, or the parsed program, to out
+func eval(out io.Writer, line string) {
+	// TODO: evaluate the program and print the result once the evaluator exists
+	printAST(out, line)
+}
+
+// printAST parses line and writes the parser errors, or the program's String(), to out
+func printAST(out io.Writer, line string) {
+	l := lexer.New(line)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if errors := p.ParseErrors(); len(errors) != 0 {
+		printParserErrors(out, errors)
+		return
+	}
+
+	fmt.Fprintln(out, program.String())
+}
+
+// printParserErrors writes each parser error to out, prefixed with its line and column
+func printParserErrors(out io.Writer, errors []parser.ParseError) {
+	for _, e := range errors {
+		fmt.Fprintf(out, "\t%s\n", e.Error())
+	}
+}
+
+// RunFile reads and parses the program at path; out receives the program's output. If the program does not parse, the
+// returned error lists the parser errors one per line, each prefixed with the path, line, and column.
+func RunFile(path string, out io.Writer) error {
+	input, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	l := lexer.New(string(input))
+	p := parser.New(l)
+	p.ParseProgram()
+
+	if errors := p.ParseErrors(); len(errors) != 0 {
+		messages := make([]string, len(errors))
+		for i, e := range errors {
+			messages[i] = path + ":" + e.Error()
+		}
+		return fmt.Errorf("%s", strings.Join(messages, "\n"))
+	}
+
+	// TODO: evaluate the program against a fresh environment once the evaluator exists
+	return nil
+}
@@ -0,0 +1,80 @@
+package repl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1 + 2", ">> let x = (1 + 2);\n>> "},
+		{"let = 5", ">> \t1:5: expected next token to be IDENT, got = instead\n>> "},
+		{
+			":tokens\nx;\n:ast\n-a * b\n:eval\nx",
+			">> >> {Type:IDENT Literal:x Line:1 Column:1 Offset:0 Continued:false}\n" +
+				"{Type:; Literal:; Line:1 Column:2 Offset:1 Continued:false}\n" +
+				">> >> ((-a) * b)\n>> >> x\n>> ",
+		},
+		// a mode command may be surrounded by spaces; anything else is input
+		{"  :ast  \n:unknown", ">> >> \t1:1: no prefix parse function for : found\n>> "},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		Start(strings.NewReader(tt.input), &out)
+
+		if out.String() != tt.expected {
+			t.Errorf("wrong output for %q.\nexpected=%q\ngot=%q", tt.input, tt.expected, out.String())
+		}
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x = 5;\nx + 1;", ""},
+		{"let = 5;\nlet x 5;",
+			"{path}:1:5: expected next token to be IDENT, got = instead\n" +
+				"{path}:2:7: expected next token to be =, got INT instead"},
+	}
+
+	for i, tt := range tests {
+		path := filepath.Join(dir, fmt.Sprintf("program%d", i))
+		if err := ioutil.WriteFile(path, []byte(tt.input), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var out bytes.Buffer
+		err := RunFile(path, &out)
+		if out.Len() != 0 {
+			t.Errorf("RunFile wrote to out for %q. got=%q", tt.input, out.String())
+		}
+		expected := strings.Replace(tt.expectedError, "{path}", path, -1)
+		switch {
+		case expected == "" && err != nil:
+			t.Errorf("RunFile returned an error for %q: %s", tt.input, err)
+		case expected != "" && (err == nil || err.Error() != expected):
+			t.Errorf("wrong error for %q. expected=%q, got=%v", tt.input, expected, err)
+		}
+	}
+
+	if err := RunFile(filepath.Join(dir, "missing"), ioutil.Discard); err == nil {
+		t.Errorf("RunFile returned no error for a missing file")
+	}
+}
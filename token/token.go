@@ -7,16 +7,32 @@ type Type string
 type Token struct {
 	Type    Type   // string;
 	Literal string // string; has the advantage of being easy to debug
+	Line    int    // line of the token's first character, starting at 1
+	Column  int    // column of the token's first character, starting at 1
+	Offset  int    // byte offset of the token's first character in the input
+	// Continued is true if a line continuation joins the token to the line before it, so it is on the same logical line
+	Continued bool
 }
 
 var keywords = map[string]Type{
-	"else":   ELSE,
-	"false":  FALSE,
-	"fn":     FUNCTION,
-	"if":     IF,
-	"let":    LET,
-	"return": RETURN,
-	"true":   TRUE,
+	"break":    BREAK,
+	"case":     CASE,
+	"catch":    CATCH,
+	"continue": CONTINUE,
+	"default":  DEFAULT,
+	"do":       DO,
+	"else":     ELSE,
+	"false":    FALSE,
+	"fn":       FUNCTION,
+	"for":      FOR,
+	"if":       IF,
+	"let":      LET,
+	"null":     NULL,
+	"return":   RETURN,
+	"switch":   SWITCH,
+	"true":     TRUE,
+	"try":      TRY,
+	"while":    WHILE,
 }
 
 // Define the possible Token.Type as constants
@@ -29,6 +45,9 @@ const (
 	// EOF is end of file
 	EOF = "EOF"
 
+	// COMMENT is a // or /* */ comment, only produced by a lexer created with lexer.NewWithComments
+	COMMENT = "COMMENT"
+
 	// ILLEGAL an illegal or unknown token type
 	ILLEGAL = "ILLEGAL"
 
@@ -42,6 +61,9 @@ const (
 	// INT is an integer type
 	INT = "INT"
 
+	// CHAR is a character type
+	CHAR = "CHAR"
+
 	//
 	// Operators
 	//
@@ -55,12 +77,21 @@ const (
 	// BANG is an operator type
 	BANG = "!"
 
+	// COALESCE is an operator type
+	COALESCE = "??"
+
 	// EQ is an operator type
 	EQ = "=="
 
+	// DEC is an operator type
+	DEC = "--"
+
 	// GT is an operator type
 	GT = ">"
 
+	// INC is an operator type
+	INC = "++"
+
 	// LT is an operator type
 	LT = "<"
 
@@ -73,6 +104,9 @@ const (
 	// PLUS is an operator type
 	PLUS = "+"
 
+	// POW is an operator type
+	POW = "**"
+
 	// SLASH is an operator type
 	SLASH = "/"
 
@@ -80,9 +114,15 @@ const (
 	// Delimiters
 	//
 
+	// COLON is a delimiter type
+	COLON = ":"
+
 	// COMMA is a delimiter type
 	COMMA = ","
 
+	// DOT is a delimiter type
+	DOT = "."
+
 	// LBRACE  is a delimiter type
 	LBRACE = "{"
 
@@ -102,12 +142,33 @@ const (
 	// Keywords
 	//
 
+	// BREAK is a keyword type
+	BREAK = "BREAK"
+
+	// CASE is a keyword type
+	CASE = "CASE"
+
+	// CATCH is a keyword type
+	CATCH = "CATCH"
+
+	// CONTINUE is a keyword type
+	CONTINUE = "CONTINUE"
+
+	// DEFAULT is a keyword type
+	DEFAULT = "DEFAULT"
+
+	// DO is a keyword type
+	DO = "DO"
+
 	// ELSE is a keyword type
 	ELSE = "ELSE"
 
 	// FALSE is a keyword type
 	FALSE = "FALSE"
 
+	// FOR is a keyword type
+	FOR = "FOR"
+
 	// FUNCTION is a keyword type
 	FUNCTION = "FUNCTION"
 
@@ -117,11 +178,23 @@ const (
 	// LET is a keyword type
 	LET = "LET"
 
+	// NULL is a keyword type
+	NULL = "NULL"
+
 	// RETURN is a keyword type
 	RETURN = "RETURN"
 
+	// SWITCH is a keyword type
+	SWITCH = "SWITCH"
+
 	// TRUE is a keyword type
 	TRUE = "TRUE"
+
+	// TRY is a keyword type
+	TRY = "TRY"
+
+	// WHILE is a keyword type
+	WHILE = "WHILE"
 )
 
 // LookupIdent returns a keyword's constant if found, or IDENT if not, as the token.Type